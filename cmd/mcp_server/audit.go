@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"template_cli/internal/audit"
+)
+
+// runAuditCommand runs an audit subcommand and returns the process exit code
+// It runs before the logger is initialized, so checking the audit log doesn't truncate the server's log
+func runAuditCommand(args []string) int {
+	if len(args) == 0 || args[0] != "verify" || len(args) > 3 {
+		fmt.Fprintln(os.Stderr, "Usage: mcp_server audit verify [audit_log_file [audit_head_file]]")
+		return 2
+	}
+
+	cfg, err := audit.NewConfigFromEnv(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load audit config from environment: %v\n", err)
+		return 2
+	}
+	if cfg.Key == "" {
+		fmt.Fprintln(os.Stderr, "MCP_AUDIT_KEY must be set to the key the audit log was written with")
+		return 2
+	}
+	path, headPath := cfg.File, cfg.Head
+	if len(args) > 1 {
+		path = args[1]
+	}
+	if len(args) > 2 {
+		headPath = args[2]
+	}
+	if headPath == "" {
+		fmt.Fprintln(os.Stderr, "The audit log head file must be given or set in MCP_AUDIT_HEAD")
+		return 2
+	}
+
+	count, err := audit.VerifyFile(path, headPath, []byte(cfg.Key))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Audit log %s failed verification: %v\n", path, err)
+		if count > 0 {
+			fmt.Fprintf(os.Stderr, "The first %d records are intact\n", count)
+		}
+		return 1
+	}
+	fmt.Printf("Audit log %s verified: %d records, hash chain intact up to its head\n", path, count)
+	return 0
+}
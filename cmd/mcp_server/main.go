@@ -3,16 +3,24 @@ package main
 import (
 	"context"
 	stdlog "log"
+	"os"
 
 	"template_cli/internal/appcontext"
 	"template_cli/internal/argoclient"
+	"template_cli/internal/audit"
 	"template_cli/internal/log"
+	"template_cli/internal/policy"
 	"template_cli/internal/tools/argo"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func main() {
+	// mcp_server audit verify checks the audit log instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(runAuditCommand(os.Args[2:]))
+	}
+
 	// Initialize logger
 	if err := log.Init(); err != nil {
 		stdlog.Fatalf("Failed to initialize logger: %v", err)
@@ -22,7 +30,7 @@ func main() {
 	l := log.Logger()
 
 	// Initialize ArgoCD client
-	// Client config will be read from environment variables (ARGOCD_BASE_URL, ARGOCD_API_TOKEN, ARGOCD_INSECURE)
+	// Client config will be read from environment variables (ARGOCD_BASE_URL, ARGOCD_API_TOKEN, ARGOCD_INSECURE, ARGOCD_READ_ONLY)
 	cfg, err := argoclient.NewConfigFromEnv(context.Background())
 	if err != nil {
 		l.Fatalw("Failed to load ArgoCD config from environment", "error", err)
@@ -39,8 +47,98 @@ func main() {
 
 	// Create a server with multiple tools.
 	server := mcp.NewServer(&mcp.Implementation{Name: "bw-mcp", Version: "v1.0.0"}, nil)
-	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_clusters", Description: "list Argo CD clusters"}, argo.NewListClustersHandler(appCtx))
-	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_applications", Description: "list Argo CD applications with optional filters"}, argo.NewListApplicationsHandler(appCtx))
+
+	// Check every tool call against the policy file, if one is configured (MCP_POLICY_FILE)
+	policyCfg, err := policy.NewConfigFromEnv(context.Background())
+	if err != nil {
+		l.Fatalw("Failed to load policy config from environment", "error", err)
+	}
+	if policyCfg.File != "" {
+		pol, err := policy.Load(policyCfg.File)
+		if err != nil {
+			l.Fatalw("Failed to load policy", "file", policyCfg.File, "error", err)
+		}
+		server.AddReceivingMiddleware(argo.NewPolicyMiddleware(appCtx, pol))
+		l.Infow("Policy enabled", "file", policyCfg.File, "rules", len(pol.Rules), "default_action", pol.DefaultAction)
+	}
+
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_clusters", Description: "list Argo CD clusters", Annotations: argo.ReadOnlyAnnotations()}, argo.NewListClustersHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_applications", Description: "list Argo CD applications with optional filters", Annotations: argo.ReadOnlyAnnotations()}, argo.NewListApplicationsHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_application", Description: "get full detail of a single Argo CD application including sources, destination, sync, health, operation state and conditions", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetApplicationHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_resource_tree", Description: "get the resource hierarchy of an Argo CD application with per-node health, kind, namespace and images", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetResourceTreeHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_application_diff", Description: "diff the live and target state of an Argo CD application's managed resources to explain why it is out of sync", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetApplicationDiffHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_events", Description: "get Kubernetes events for an Argo CD application or one of its managed resources, newest first", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetEventsHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_pod_logs", Description: "get logs of a pod or of all pods of a workload managed by an Argo CD application, capped in size", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetPodLogsHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_application_history", Description: "get the deployment history of an Argo CD application, with commit author, date and message for each revision", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetApplicationHistoryHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_manifests", Description: "get the rendered Kubernetes manifests of an Argo CD application for its target or a given revision, with kind/name filters and a summary mode", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetManifestsHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_live_resource", Description: "get the live manifest of a single resource managed by an Argo CD application, as it is running in the cluster", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetLiveResourceHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_projects", Description: "list Argo CD projects with their source repos, destinations, resource allow/deny lists, roles and sync windows", Annotations: argo.ReadOnlyAnnotations()}, argo.NewListProjectsHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_project", Description: "get a typed summary of a single Argo CD project including source repos, destinations, resource allow/deny lists, roles, sync windows and orphaned resource settings", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetProjectHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_repositories", Description: "list repositories configured in Argo CD with their type, project and connection state; credentials are always redacted", Annotations: argo.ReadOnlyAnnotations()}, argo.NewListRepositoriesHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_get_repository", Description: "get a single Argo CD repository with its type, project and connection state, optionally re-checking connectivity; credentials are always redacted", Annotations: argo.ReadOnlyAnnotations()}, argo.NewGetRepositoryHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_wait_for_application", Description: "wait for an Argo CD application to become synced, healthy, finish its operation or be suspended, streaming progress and returning a timeline of transitions", Annotations: argo.ReadOnlyAnnotations()}, argo.NewWaitForApplicationHandler(appCtx))
+	mcp.AddTool(server, &mcp.Tool{Name: "argocd_list_resource_actions", Description: "list the resource actions, such as restart, that Argo CD offers for a resource managed by an application", Annotations: argo.ReadOnlyAnnotations()}, argo.NewListResourceActionsHandler(appCtx))
+
+	// Write tools are not registered at all in read-only mode; the Argo CD client refuses writes as well
+	if cfg.ReadOnly {
+		l.Info("Read-only mode enabled, write tools are not registered")
+	} else {
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_sync_application", Description: "sync an Argo CD application, optionally to a given revision, with prune, dry-run, a resource subset, sync options and apply or hook strategy; syncs with prune ask the user to confirm first", Annotations: argo.WriteAnnotations(true, false)}, argo.NewSyncApplicationHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_rollback_application", Description: "roll an Argo CD application back to a deployment history entry and explain how it differs from what is currently deployed, after the user confirms; auto-sync must be disabled", Annotations: argo.WriteAnnotations(true, false)}, argo.NewRollbackApplicationHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_refresh_application", Description: "trigger a normal or hard refresh of an Argo CD application so it re-reads git, and update it in the application cache", Annotations: argo.WriteAnnotations(false, true)}, argo.NewRefreshApplicationHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_terminate_operation", Description: "terminate the running sync operation of an Argo CD application, reporting its state and hooks before and confirming the final phase after", Annotations: argo.WriteAnnotations(true, true)}, argo.NewTerminateOperationHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_run_resource_action", Description: "run a resource action, such as restarting a Deployment, on a resource managed by an Argo CD application, after the user confirms", Annotations: argo.WriteAnnotations(true, false)}, argo.NewRunResourceActionHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_delete_resource", Description: "delete a single live resource managed by an Argo CD application, optionally orphaning its dependents or forcing deletion, after the user confirms", Annotations: argo.WriteAnnotations(true, true)}, argo.NewDeleteResourceHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_update_application", Description: "edit an Argo CD application spec like argocd app set: target revision, Helm parameters and value files, Kustomize images and name prefix, sync policy and destination namespace, returning a diff of the spec; dry runs validate the edited spec like a real update", Annotations: argo.WriteAnnotations(true, true)}, argo.NewUpdateApplicationHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_create_application", Description: "create an Argo CD application from a repository path or Helm chart, or several sources, after checking that its project allows the source and destination; supports upsert and validate-only modes", Annotations: argo.WriteAnnotations(true, true)}, argo.NewCreateApplicationHandler(appCtx))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_delete_application", Description: "delete an Argo CD application with or without its resources, listing what is removed and asking the user to confirm first; app-of-apps parents are refused unless forced", Annotations: argo.WriteAnnotations(true, true)}, argo.NewDeleteApplicationHandler(appCtx))
+
+		// Clients that can't ask the user get one-time confirmation codes through a file only the user reads (MCP_CONFIRMATION_FILE)
+		confirmCfg, err := argo.NewConfirmationConfigFromEnv(context.Background())
+		if err != nil {
+			l.Fatalw("Failed to load confirmation config from environment", "error", err)
+		}
+		if confirmCfg.File != "" {
+			confirmFile, err := os.OpenFile(confirmCfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+			if err != nil {
+				l.Fatalw("Failed to open confirmation file", "file", confirmCfg.File, "error", err)
+			}
+			defer confirmFile.Close()
+			argo.EnableConfirmationCodes(confirmFile)
+			l.Infow("Confirmation codes enabled", "file", confirmCfg.File)
+		}
+
+		// Record every write tool call, including those refused by the policy, in the audit log (MCP_AUDIT_LOG)
+		// It is added after the policy middleware so that it wraps it, and before the planner so that applied plans pass through it
+		auditCfg, err := audit.NewConfigFromEnv(context.Background())
+		if err != nil {
+			l.Fatalw("Failed to load audit config from environment", "error", err)
+		}
+		if auditCfg.Key == "" {
+			l.Fatalw("MCP_AUDIT_KEY must be set to sign the audit log")
+		}
+		auditLog, err := audit.Open(auditCfg.File, auditCfg.Head, []byte(auditCfg.Key))
+		if err != nil {
+			l.Fatalw("Failed to open audit log", "file", auditCfg.File, "error", err)
+		}
+		defer auditLog.Close()
+		server.AddReceivingMiddleware(audit.NewMiddleware(auditLog, argo.IsWriteTool, func(ctx context.Context) (string, error) {
+			return argoclient.CurrentUser(ctx, argoClientWithServer.Client)
+		}))
+		l.Infow("Audit log enabled", "file", auditCfg.File)
+
+		// Write tools are planned first and applied only while the application is unchanged (MCP_REQUIRE_PLAN)
+		// The planner middleware is added last, so planned calls still pass through the audit log and policy
+		planCfg, err := argo.NewPlanConfigFromEnv(context.Background())
+		if err != nil {
+			l.Fatalw("Failed to load plan config from environment", "error", err)
+		}
+		planner := argo.NewPlanner(appCtx, planCfg.Require)
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_plan_change", Description: "plan a call to an Argo CD write tool: returns what it would change from a dry run, managed resource diffs and a spec diff, plus a plan ID bound to the current application state; show the plan to the user before applying it", Annotations: argo.WriteAnnotations(false, true)}, argo.NewPlanChangeHandler(planner))
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_apply_change", Description: "apply a plan from argocd_plan_change, only if the application is still in the state the plan was made from; plans expire after 15 minutes and apply once", Annotations: argo.WriteAnnotations(true, false)}, argo.NewApplyChangeHandler(planner))
+		server.AddReceivingMiddleware(planner.Middleware())
+		l.Infow("Plan workflow enabled", "required", planCfg.Require)
+	}
 
 	l.Info("MCP server initialized, starting server loop")
 
@@ -46,6 +46,100 @@ func (ac *AppContext) GetCachedApplications() *ApplicationCache {
 	return ac.applicationCache
 }
 
+// FindCachedApplication looks up a single application in the cache by name
+// An empty appNamespace matches applications in any namespace
+// Returns nil if the cache is expired, doesn't exist or has no matching application
+func (ac *AppContext) FindCachedApplication(name, appNamespace string) *v1alpha1.Application {
+	cache := ac.GetCachedApplications()
+	if cache == nil {
+		return nil
+	}
+
+	for i := range cache.Items {
+		app := &cache.Items[i]
+		if app.Name != name {
+			continue
+		}
+		if appNamespace != "" && app.Namespace != appNamespace {
+			continue
+		}
+		// Return a copy so callers can't mutate the shared cache
+		return app.DeepCopy()
+	}
+
+	return nil
+}
+
+// UpsertCachedApplication replaces a single application in the cache, or adds it if it isn't cached yet
+// The cache is left untouched if it is expired or doesn't exist, and its expiry is not extended
+func (ac *AppContext) UpsertCachedApplication(app *v1alpha1.Application) {
+	ac.applicationCacheMutex.Lock()
+	defer ac.applicationCacheMutex.Unlock()
+
+	if ac.applicationCache == nil || time.Now().After(ac.applicationCache.ExpiresAt) {
+		return
+	}
+
+	// Build a new slice rather than editing in place, since readers hold the old cache outside the lock
+	items := make([]v1alpha1.Application, 0, len(ac.applicationCache.Items)+1)
+	replaced := false
+	for _, cached := range ac.applicationCache.Items {
+		if cached.Name == app.Name && cached.Namespace == app.Namespace {
+			items = append(items, *app.DeepCopy())
+			replaced = true
+			continue
+		}
+		items = append(items, cached)
+	}
+	if !replaced {
+		items = append(items, *app.DeepCopy())
+	}
+
+	ac.applicationCache = &ApplicationCache{
+		Items:     items,
+		CachedAt:  ac.applicationCache.CachedAt,
+		ExpiresAt: ac.applicationCache.ExpiresAt,
+	}
+
+	// Persist to disk
+	if err := ac.writeApplicationCacheToDisk(); err != nil {
+		log.Logger().Warnw("Failed to write application cache to disk", "error", err)
+	}
+}
+
+// RemoveCachedApplication drops a single application from the cache, e.g. after it was deleted
+func (ac *AppContext) RemoveCachedApplication(name, appNamespace string) {
+	ac.applicationCacheMutex.Lock()
+	defer ac.applicationCacheMutex.Unlock()
+
+	if ac.applicationCache == nil || time.Now().After(ac.applicationCache.ExpiresAt) {
+		return
+	}
+
+	// Build a new slice rather than editing in place, since readers hold the old cache outside the lock
+	items := make([]v1alpha1.Application, 0, len(ac.applicationCache.Items))
+	for _, cached := range ac.applicationCache.Items {
+		if cached.Name == name && cached.Namespace == appNamespace {
+			continue
+		}
+		items = append(items, cached)
+	}
+	if len(items) == len(ac.applicationCache.Items) {
+		return
+	}
+
+	ac.applicationCache = &ApplicationCache{
+		Items:     items,
+		CachedAt:  ac.applicationCache.CachedAt,
+		ExpiresAt: ac.applicationCache.ExpiresAt,
+	}
+
+	// Persist to disk
+	if err := ac.writeApplicationCacheToDisk(); err != nil {
+		log.Logger().Warnw("Failed to write application cache to disk", "error", err)
+	}
+}
+
 // SetApplicationCache updates the application cache with the given items and TTL
 func (ac *AppContext) SetApplicationCache(items []v1alpha1.Application, ttl time.Duration) {
 	ac.applicationCacheMutex.Lock()
@@ -97,6 +97,151 @@ var _ = Describe("ApplicationCache", func() {
 		})
 	})
 
+	Describe("FindCachedApplication", func() {
+		BeforeEach(func() {
+			apps := []v1alpha1.Application{
+				{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "team-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "argocd"}},
+			}
+			ac.applicationCache = &ApplicationCache{
+				Items:     apps,
+				CachedAt:  time.Now(),
+				ExpiresAt: time.Now().Add(1 * time.Hour),
+			}
+		})
+
+		DescribeTable("lookup by name and namespace",
+			func(name, appNamespace string, expectFound bool, expectedNamespace string) {
+				result := ac.FindCachedApplication(name, appNamespace)
+				if !expectFound {
+					Expect(result).To(BeNil())
+					return
+				}
+				Expect(result).NotTo(BeNil())
+				Expect(result.Name).To(Equal(name))
+				Expect(result.Namespace).To(Equal(expectedNamespace))
+			},
+			Entry("matches name in any namespace", "billing", "", true, "argocd"),
+			Entry("matches name in given namespace", "guestbook", "team-a", true, "team-a"),
+			Entry("no match for unknown name", "unknown", "", false, ""),
+			Entry("no match for wrong namespace", "billing", "team-a", false, ""),
+		)
+
+		It("should return a copy of the cached application", func() {
+			result := ac.FindCachedApplication("billing", "argocd")
+			Expect(result).NotTo(BeNil())
+			result.Spec.Project = "mutated"
+			Expect(ac.applicationCache.Items[2].Spec.Project).To(BeEmpty())
+		})
+
+		It("should return nil when the cache is expired", func() {
+			ac.applicationCache.ExpiresAt = time.Now().Add(-1 * time.Minute)
+			Expect(ac.FindCachedApplication("billing", "")).To(BeNil())
+		})
+	})
+
+	Describe("UpsertCachedApplication", func() {
+		var expiresAt time.Time
+
+		BeforeEach(func() {
+			expiresAt = time.Now().Add(1 * time.Hour)
+			ac.applicationCache = &ApplicationCache{
+				Items: []v1alpha1.Application{
+					{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "argocd"}},
+				},
+				CachedAt:  time.Now(),
+				ExpiresAt: expiresAt,
+			}
+		})
+
+		It("should replace an existing application", func() {
+			app := &v1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "argocd"},
+				Spec:       v1alpha1.ApplicationSpec{Project: "payments"},
+			}
+			ac.UpsertCachedApplication(app)
+
+			Expect(ac.applicationCache.Items).To(HaveLen(2))
+			Expect(ac.FindCachedApplication("billing", "argocd").Spec.Project).To(Equal("payments"))
+			Expect(ac.applicationCache.ExpiresAt).To(Equal(expiresAt))
+		})
+
+		It("should add a missing application", func() {
+			ac.UpsertCachedApplication(&v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "team-a"}})
+
+			Expect(ac.applicationCache.Items).To(HaveLen(3))
+			Expect(ac.FindCachedApplication("billing", "team-a")).NotTo(BeNil())
+		})
+
+		It("should not modify a cache snapshot held by readers", func() {
+			snapshot := ac.GetCachedApplications()
+			ac.UpsertCachedApplication(&v1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "argocd"},
+				Spec:       v1alpha1.ApplicationSpec{Project: "payments"},
+			})
+
+			Expect(snapshot.Items[1].Spec.Project).To(BeEmpty())
+		})
+
+		It("should not populate an expired cache", func() {
+			ac.applicationCache.ExpiresAt = time.Now().Add(-1 * time.Minute)
+			ac.UpsertCachedApplication(&v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "argocd"}})
+
+			Expect(ac.applicationCache.Items).To(HaveLen(2))
+		})
+
+		It("should not create a missing cache", func() {
+			ac.applicationCache = nil
+			ac.UpsertCachedApplication(&v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "argocd"}})
+
+			Expect(ac.applicationCache).To(BeNil())
+		})
+	})
+
+	Describe("RemoveCachedApplication", func() {
+		BeforeEach(func() {
+			ac.applicationCache = &ApplicationCache{
+				Items: []v1alpha1.Application{
+					{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "argocd"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "team-a"}},
+				},
+				CachedAt:  time.Now(),
+				ExpiresAt: time.Now().Add(1 * time.Hour),
+			}
+		})
+
+		It("should remove only the matching application", func() {
+			ac.RemoveCachedApplication("billing", "argocd")
+
+			Expect(ac.applicationCache.Items).To(HaveLen(2))
+			Expect(ac.FindCachedApplication("billing", "argocd")).To(BeNil())
+			Expect(ac.FindCachedApplication("billing", "team-a")).NotTo(BeNil())
+		})
+
+		It("should not modify a cache snapshot held by readers", func() {
+			snapshot := ac.GetCachedApplications()
+			ac.RemoveCachedApplication("guestbook", "argocd")
+
+			Expect(snapshot.Items).To(HaveLen(3))
+		})
+
+		It("should ignore applications that aren't cached", func() {
+			ac.RemoveCachedApplication("unknown", "argocd")
+
+			Expect(ac.applicationCache.Items).To(HaveLen(3))
+		})
+
+		It("should not touch a missing cache", func() {
+			ac.applicationCache = nil
+			ac.RemoveCachedApplication("guestbook", "argocd")
+
+			Expect(ac.applicationCache).To(BeNil())
+		})
+	})
+
 	Describe("Cache Concurrency", func() {
 		It("should handle concurrent operations safely", func() {
 			var wg sync.WaitGroup
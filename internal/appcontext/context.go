@@ -32,6 +32,10 @@ type AppContext struct {
 	// ApplicationCache holds cached application information
 	applicationCache      *ApplicationCache
 	applicationCacheMutex sync.RWMutex
+
+	// ProjectCache holds cached project information
+	projectCache      *ProjectCache
+	projectCacheMutex sync.RWMutex
 }
 
 // ServerConfig represents cached server configuration
@@ -66,6 +70,7 @@ func NewAppContext(argoClient apiclient.Client, argoServer string) *AppContext {
 	// Try to load existing caches from disk
 	ctx.loadClusterCacheFromDisk()
 	ctx.loadApplicationCacheFromDisk()
+	ctx.loadProjectCacheFromDisk()
 
 	return ctx
 }
@@ -120,6 +125,7 @@ func (ctx *AppContext) deleteAllCaches() {
 	cacheFiles := []string{
 		ClusterCacheFile,
 		ApplicationCacheFile,
+		ProjectCacheFile,
 		// Add more cache files here as they are added to the system
 	}
 
@@ -138,4 +144,8 @@ func (ctx *AppContext) deleteAllCaches() {
 	ctx.applicationCacheMutex.Lock()
 	ctx.applicationCache = nil
 	ctx.applicationCacheMutex.Unlock()
+
+	ctx.projectCacheMutex.Lock()
+	ctx.projectCache = nil
+	ctx.projectCacheMutex.Unlock()
 }
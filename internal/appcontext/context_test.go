@@ -50,6 +50,7 @@ var _ = Describe("AppContext", func() {
 			ctx = &AppContext{
 				clusterCacheMutex:     sync.RWMutex{},
 				applicationCacheMutex: sync.RWMutex{},
+				projectCacheMutex:     sync.RWMutex{},
 			}
 
 			// Set some in-memory caches
@@ -65,12 +66,20 @@ var _ = Describe("AppContext", func() {
 				ExpiresAt: time.Now().Add(1 * time.Hour),
 			}
 
+			ctx.projectCache = &ProjectCache{
+				Items:     createTestProjects(1),
+				CachedAt:  time.Now(),
+				ExpiresAt: time.Now().Add(1 * time.Hour),
+			}
+
 			// Create cache files on disk
 			clusterCachePath := filepath.Join(log.ContextDir, ClusterCacheFile)
 			appCachePath := filepath.Join(log.ContextDir, ApplicationCacheFile)
+			projectCachePath := filepath.Join(log.ContextDir, ProjectCacheFile)
 
 			Expect(os.WriteFile(clusterCachePath, []byte("{}"), 0644)).To(Succeed())
 			Expect(os.WriteFile(appCachePath, []byte("{}"), 0644)).To(Succeed())
+			Expect(os.WriteFile(projectCachePath, []byte("{}"), 0644)).To(Succeed())
 		})
 
 		It("should clear in-memory caches", func() {
@@ -78,16 +87,19 @@ var _ = Describe("AppContext", func() {
 
 			Expect(ctx.clusterCache).To(BeNil())
 			Expect(ctx.applicationCache).To(BeNil())
+			Expect(ctx.projectCache).To(BeNil())
 		})
 
 		It("should delete cache files from disk", func() {
 			clusterCachePath := filepath.Join(log.ContextDir, ClusterCacheFile)
 			appCachePath := filepath.Join(log.ContextDir, ApplicationCacheFile)
+			projectCachePath := filepath.Join(log.ContextDir, ProjectCacheFile)
 
 			ctx.deleteAllCaches()
 
 			Expect(clusterCachePath).NotTo(BeAnExistingFile())
 			Expect(appCachePath).NotTo(BeAnExistingFile())
+			Expect(projectCachePath).NotTo(BeAnExistingFile())
 		})
 
 		Context("when cache files don't exist", func() {
@@ -231,6 +243,8 @@ var _ = Describe("AppContext", func() {
 			Entry("ServerConfigFile", ServerConfigFile, "server_config.json"),
 			Entry("ApplicationCacheFile", ApplicationCacheFile, "application_cache.json"),
 			Entry("ApplicationCacheTTL", ApplicationCacheTTL, 60*time.Minute),
+			Entry("ProjectCacheFile", ProjectCacheFile, "project_cache.json"),
+			Entry("ProjectCacheTTL", ProjectCacheTTL, 60*time.Minute),
 		)
 	})
 })
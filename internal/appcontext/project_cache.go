@@ -0,0 +1,194 @@
+package appcontext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	// ProjectCacheFile is the filename for the project cache
+	ProjectCacheFile = "project_cache.json"
+
+	// ProjectCacheTTL is the default time-to-live for project cache
+	ProjectCacheTTL = 60 * time.Minute
+)
+
+// ProjectCache represents cached project list data
+type ProjectCache struct {
+	Items     []v1alpha1.AppProject `json:"items"`
+	CachedAt  time.Time             `json:"cached_at"`
+	ExpiresAt time.Time             `json:"expires_at"`
+}
+
+// GetCachedProjects retrieves the cached project list if it's still valid
+// Returns nil if cache is expired or doesn't exist
+func (ctx *AppContext) GetCachedProjects() *ProjectCache {
+	ctx.projectCacheMutex.RLock()
+	defer ctx.projectCacheMutex.RUnlock()
+
+	if ctx.projectCache == nil {
+		return nil
+	}
+
+	if time.Now().After(ctx.projectCache.ExpiresAt) {
+		return nil
+	}
+
+	return ctx.projectCache
+}
+
+// FindCachedProject looks up a single project in the cache by name
+// Returns nil if the cache is expired, doesn't exist or has no matching project
+func (ctx *AppContext) FindCachedProject(name string) *v1alpha1.AppProject {
+	cache := ctx.GetCachedProjects()
+	if cache == nil {
+		return nil
+	}
+
+	for i := range cache.Items {
+		if cache.Items[i].Name == name {
+			// Return a copy so callers can't mutate the shared cache
+			return cache.Items[i].DeepCopy()
+		}
+	}
+
+	return nil
+}
+
+// SetProjectCache updates the project cache with the given items and TTL
+func (ctx *AppContext) SetProjectCache(items []v1alpha1.AppProject, ttl time.Duration) {
+	ctx.projectCacheMutex.Lock()
+	defer ctx.projectCacheMutex.Unlock()
+
+	now := time.Now()
+	ctx.projectCache = &ProjectCache{
+		Items:     items,
+		CachedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	// Persist to disk
+	if err := ctx.writeProjectCacheToDisk(); err != nil {
+		log.Logger().Warnw("Failed to write project cache to disk", "error", err)
+	}
+}
+
+// InvalidateProjectCache clears the project cache
+func (ctx *AppContext) InvalidateProjectCache() {
+	ctx.projectCacheMutex.Lock()
+	defer ctx.projectCacheMutex.Unlock()
+
+	ctx.projectCache = nil
+
+	// Remove cache file from disk
+	cachePath := filepath.Join(log.ContextDir, ProjectCacheFile)
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		log.Logger().Warnw("Failed to remove project cache file", "error", err)
+	}
+}
+
+// RefreshProjectCache fetches fresh project data from ArgoCD and caches it
+// Returns error if the fetch fails
+func (ctx *AppContext) RefreshProjectCache(ctxIn context.Context) error {
+	l := log.Logger().With("component", "refresh_project_cache")
+
+	l.Info("Fetching fresh project data from ArgoCD")
+	conn, projectClient, err := ctx.ArgoClient.NewProjectClient()
+	if err != nil {
+		l.Errorw("Failed to create project client", "error", err)
+		return fmt.Errorf("failed to create project client: %w", err)
+	}
+	defer conn.Close()
+
+	// List projects with timing
+	listStartTime := time.Now()
+	projectList, err := projectClient.List(ctxIn, &project.ProjectQuery{})
+	listDuration := time.Since(listStartTime)
+
+	if err != nil {
+		l.Errorw("Failed to list projects", "error", err, "duration", listDuration)
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	l.Infow("Successfully fetched projects from ArgoCD", "count", len(projectList.Items), "duration", listDuration.String())
+
+	// Cache the results
+	ctx.SetProjectCache(projectList.Items, ProjectCacheTTL)
+
+	return nil
+}
+
+// writeProjectCacheToDisk persists the project cache to disk (caller must hold lock)
+func (ctx *AppContext) writeProjectCacheToDisk() error {
+	if ctx.projectCache == nil {
+		return nil
+	}
+
+	cachePath := filepath.Join(log.ContextDir, ProjectCacheFile)
+
+	data, err := json.MarshalIndent(ctx.projectCache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal project cache: %w", err)
+	}
+
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write project cache file: %w", err)
+	}
+
+	return nil
+}
+
+// loadProjectCacheFromDisk loads the project cache from disk if it exists and is valid
+// If the cache is expired, it will be refreshed from ArgoCD
+func (ctx *AppContext) loadProjectCacheFromDisk() {
+	ctx.projectCacheMutex.Lock()
+	cachePath := filepath.Join(log.ContextDir, ProjectCacheFile)
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Logger().Warnw("Failed to read project cache file", "error", err)
+		}
+		ctx.projectCacheMutex.Unlock()
+		// No cache exists, attempt to refresh
+		log.Logger().Info("No project cache found, fetching fresh data")
+		if err := ctx.RefreshProjectCache(context.Background()); err != nil {
+			log.Logger().Warnw("Failed to refresh project cache on startup", "error", err)
+		}
+		return
+	}
+
+	var cache ProjectCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		log.Logger().Warnw("Failed to unmarshal project cache", "error", err)
+		ctx.projectCacheMutex.Unlock()
+		return
+	}
+
+	// Check if cache is expired
+	if time.Now().After(cache.ExpiresAt) {
+		// Cache is expired, remove the file
+		os.Remove(cachePath)
+		ctx.projectCacheMutex.Unlock()
+
+		// Refresh the cache with fresh data
+		log.Logger().Info("Project cache expired, fetching fresh data")
+		if err := ctx.RefreshProjectCache(context.Background()); err != nil {
+			log.Logger().Warnw("Failed to refresh expired project cache", "error", err)
+		}
+		return
+	}
+
+	// Cache is valid, use it
+	ctx.projectCache = &cache
+	ctx.projectCacheMutex.Unlock()
+}
@@ -0,0 +1,258 @@
+package appcontext
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ProjectCache", func() {
+	var ctx *AppContext
+
+	BeforeEach(func() {
+		ctx = &AppContext{
+			projectCacheMutex: sync.RWMutex{},
+		}
+	})
+
+	Describe("GetCachedProjects", func() {
+		Context("when cache is nil", func() {
+			It("should return nil", func() {
+				result := ctx.GetCachedProjects()
+				Expect(result).To(BeNil())
+			})
+		})
+
+		Context("when cache is valid", func() {
+			BeforeEach(func() {
+				ctx.projectCache = &ProjectCache{
+					Items:     createTestProjects(3),
+					CachedAt:  time.Now().Add(-30 * time.Minute),
+					ExpiresAt: time.Now().Add(30 * time.Minute),
+				}
+			})
+
+			It("should return the cached items", func() {
+				result := ctx.GetCachedProjects()
+				Expect(result).NotTo(BeNil())
+				Expect(result.Items).To(HaveLen(3))
+			})
+		})
+
+		Context("when cache is expired", func() {
+			BeforeEach(func() {
+				ctx.projectCache = &ProjectCache{
+					Items:     createTestProjects(2),
+					CachedAt:  time.Now().Add(-2 * time.Hour),
+					ExpiresAt: time.Now().Add(-1 * time.Hour),
+				}
+			})
+
+			It("should return nil", func() {
+				result := ctx.GetCachedProjects()
+				Expect(result).To(BeNil())
+			})
+		})
+	})
+
+	Describe("FindCachedProject", func() {
+		Context("when cache is nil", func() {
+			It("should return nil", func() {
+				Expect(ctx.FindCachedProject("project-0")).To(BeNil())
+			})
+		})
+
+		Context("when cache is valid", func() {
+			BeforeEach(func() {
+				ctx.SetProjectCache(createTestProjects(3), 1*time.Hour)
+			})
+
+			It("should find a project by name", func() {
+				result := ctx.FindCachedProject("project-1")
+				Expect(result).NotTo(BeNil())
+				Expect(result.Name).To(Equal("project-1"))
+			})
+
+			It("should return nil for an unknown project", func() {
+				Expect(ctx.FindCachedProject("missing")).To(BeNil())
+			})
+
+			It("should return a copy of the cached project", func() {
+				result := ctx.FindCachedProject("project-1")
+				result.Spec.SourceRepos[0] = "changed"
+
+				Expect(ctx.FindCachedProject("project-1").Spec.SourceRepos[0]).To(Equal("*"))
+			})
+		})
+
+		Context("when cache is expired", func() {
+			It("should return nil", func() {
+				ctx.SetProjectCache(createTestProjects(1), -1*time.Minute)
+				Expect(ctx.FindCachedProject("project-0")).To(BeNil())
+			})
+		})
+	})
+
+	Describe("SetProjectCache", func() {
+		DescribeTable("should set cache with different TTLs",
+			func(count int, ttl time.Duration) {
+				projects := createTestProjects(count)
+				beforeSet := time.Now()
+				ctx.SetProjectCache(projects, ttl)
+				afterSet := time.Now()
+
+				Expect(ctx.projectCache).NotTo(BeNil())
+				Expect(ctx.projectCache.Items).To(HaveLen(count))
+				Expect(ctx.projectCache.CachedAt).To(BeTemporally(">=", beforeSet))
+				Expect(ctx.projectCache.CachedAt).To(BeTemporally("<=", afterSet))
+
+				actualTTL := ctx.projectCache.ExpiresAt.Sub(ctx.projectCache.CachedAt)
+				Expect(actualTTL).To(BeNumerically("~", ttl, time.Second))
+			},
+			Entry("1 hour TTL with 2 projects", 2, 1*time.Hour),
+			Entry("30 minute TTL with 1 project", 1, 30*time.Minute),
+			Entry("45 minute TTL with 5 projects", 5, 45*time.Minute),
+		)
+	})
+
+	Describe("InvalidateProjectCache", func() {
+		BeforeEach(func() {
+			ctx.projectCache = &ProjectCache{
+				Items:     createTestProjects(2),
+				CachedAt:  time.Now(),
+				ExpiresAt: time.Now().Add(1 * time.Hour),
+			}
+		})
+
+		It("should clear the cache", func() {
+			ctx.InvalidateProjectCache()
+			Expect(ctx.projectCache).To(BeNil())
+		})
+	})
+
+	Describe("Cache Concurrency", func() {
+		It("should handle concurrent operations safely", func() {
+			var wg sync.WaitGroup
+			iterations := 100
+
+			// Concurrent writes
+			for i := 0; i < iterations; i++ {
+				wg.Add(1)
+				go func(n int) {
+					defer wg.Done()
+					projects := createTestProjects(n % 5)
+					ctx.SetProjectCache(projects, 1*time.Hour)
+				}(i)
+			}
+
+			// Concurrent reads
+			for i := 0; i < iterations; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_ = ctx.GetCachedProjects()
+				}()
+			}
+
+			// Concurrent invalidations
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					ctx.InvalidateProjectCache()
+				}()
+			}
+
+			wg.Wait()
+		})
+	})
+
+	Describe("Cache Expiration", func() {
+		It("should expire after TTL", func() {
+			projects := createTestProjects(1)
+			ctx.SetProjectCache(projects, 100*time.Millisecond)
+
+			// Should be valid immediately
+			result := ctx.GetCachedProjects()
+			Expect(result).NotTo(BeNil())
+
+			// Wait for expiration
+			time.Sleep(150 * time.Millisecond)
+
+			// Should be expired now
+			result = ctx.GetCachedProjects()
+			Expect(result).To(BeNil())
+		})
+	})
+
+	Describe("Empty Items", func() {
+		It("should handle empty project list", func() {
+			projects := []v1alpha1.AppProject{}
+			ctx.SetProjectCache(projects, 1*time.Hour)
+
+			result := ctx.GetCachedProjects()
+			Expect(result).NotTo(BeNil())
+			Expect(result.Items).To(BeEmpty())
+		})
+	})
+
+	Describe("Cache Constants", func() {
+		It("should have correct TTL constant", func() {
+			Expect(ProjectCacheTTL).To(Equal(60 * time.Minute))
+		})
+
+		It("should have correct cache file name", func() {
+			Expect(ProjectCacheFile).To(Equal("project_cache.json"))
+		})
+	})
+
+	Describe("writeProjectCacheToDisk", func() {
+		Context("when cache is nil", func() {
+			It("should not return an error", func() {
+				ctx.projectCache = nil
+				err := ctx.writeProjectCacheToDisk()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Multiple Updates", func() {
+		It("should replace previous cache", func() {
+			// First update
+			projects1 := createTestProjects(2)
+			ctx.SetProjectCache(projects1, 1*time.Hour)
+
+			result1 := ctx.GetCachedProjects()
+			Expect(result1).NotTo(BeNil())
+			Expect(result1.Items).To(HaveLen(2))
+
+			// Second update (should replace)
+			projects2 := createTestProjects(5)
+			ctx.SetProjectCache(projects2, 30*time.Minute)
+
+			result2 := ctx.GetCachedProjects()
+			Expect(result2).NotTo(BeNil())
+			Expect(result2.Items).To(HaveLen(5))
+		})
+	})
+})
+
+// Helper function to create test projects
+func createTestProjects(count int) []v1alpha1.AppProject {
+	projects := make([]v1alpha1.AppProject, count)
+	for i := 0; i < count; i++ {
+		projects[i] = v1alpha1.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("project-%d", i)},
+			Spec: v1alpha1.AppProjectSpec{
+				SourceRepos: []string{"*"},
+			},
+		}
+	}
+	return projects
+}
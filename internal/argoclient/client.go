@@ -14,6 +14,7 @@ type Config struct {
 	Server    string `env:"ARGOCD_BASE_URL,required"`
 	AuthToken string `env:"ARGOCD_API_TOKEN,required"`
 	Insecure  bool   `env:"ARGOCD_INSECURE,default=false"`
+	ReadOnly  bool   `env:"ARGOCD_READ_ONLY,default=false"`
 }
 
 // NewConfigFromEnv loads the Argo CD configuration from environment variables
@@ -33,6 +34,7 @@ type ClientWithServer struct {
 
 // NewClient creates a new Argo CD API client with the provided configuration
 // Returns the client and the normalized server URL it's connected to
+// If cfg.ReadOnly is set, the client refuses every write
 func NewClient(cfg Config) (*ClientWithServer, error) {
 	// Strip URL scheme if present (https:// or http://)
 	// The Argo CD gRPC client expects just the hostname:port
@@ -50,6 +52,9 @@ func NewClient(cfg Config) (*ClientWithServer, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Argo CD client: %w", err)
 	}
+	if cfg.ReadOnly {
+		apiClient = NewReadOnlyClient(apiClient)
+	}
 
 	return &ClientWithServer{
 		Client: apiClient,
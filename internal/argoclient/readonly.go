@@ -0,0 +1,330 @@
+package argoclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	accountpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/account"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	applicationsetpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/applicationset"
+	certificatepkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/certificate"
+	clusterpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/cluster"
+	gpgkeypkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/gpgkey"
+	projectpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
+	repocredspkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/repocreds"
+	repositorypkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
+	sessionpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/session"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"google.golang.org/grpc"
+)
+
+// ErrReadOnly is returned for every write attempted through a read-only client
+var ErrReadOnly = errors.New("argo cd client is in read-only mode")
+
+// readOnlyClient wraps an Argo CD client so that every write through it fails with ErrReadOnly
+// Services the server doesn't read from and that can write are refused entirely
+type readOnlyClient struct {
+	apiclient.Client
+}
+
+// NewReadOnlyClient wraps an Argo CD client so that it can only be used to read
+// This is a safeguard behind not registering write tools, so a missed check can't change anything
+func NewReadOnlyClient(client apiclient.Client) apiclient.Client {
+	return &readOnlyClient{Client: client}
+}
+
+// unavailable reports that a service can't be used in read-only mode
+func unavailable(service string) error {
+	return fmt.Errorf("%w: the %s service is not available", ErrReadOnly, service)
+}
+
+// orDie exits if a service client couldn't be created, like the OrDie methods of the wrapped client
+func orDie[T any](closer io.Closer, client T, err error) (io.Closer, T) {
+	if err != nil {
+		log.Logger().Fatalw("Failed to create Argo CD service client", "error", err)
+	}
+	return closer, client
+}
+
+func (c *readOnlyClient) NewApplicationClient() (io.Closer, applicationpkg.ApplicationServiceClient, error) {
+	closer, client, err := c.Client.NewApplicationClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return closer, &readOnlyApplicationClient{ApplicationServiceClient: client}, nil
+}
+
+func (c *readOnlyClient) NewApplicationClientOrDie() (io.Closer, applicationpkg.ApplicationServiceClient) {
+	return orDie(c.NewApplicationClient())
+}
+
+func (c *readOnlyClient) NewProjectClient() (io.Closer, projectpkg.ProjectServiceClient, error) {
+	closer, client, err := c.Client.NewProjectClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return closer, &readOnlyProjectClient{ProjectServiceClient: client}, nil
+}
+
+func (c *readOnlyClient) NewProjectClientOrDie() (io.Closer, projectpkg.ProjectServiceClient) {
+	return orDie(c.NewProjectClient())
+}
+
+func (c *readOnlyClient) NewRepoClient() (io.Closer, repositorypkg.RepositoryServiceClient, error) {
+	closer, client, err := c.Client.NewRepoClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return closer, &readOnlyRepoClient{RepositoryServiceClient: client}, nil
+}
+
+func (c *readOnlyClient) NewRepoClientOrDie() (io.Closer, repositorypkg.RepositoryServiceClient) {
+	return orDie(c.NewRepoClient())
+}
+
+func (c *readOnlyClient) NewClusterClient() (io.Closer, clusterpkg.ClusterServiceClient, error) {
+	closer, client, err := c.Client.NewClusterClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return closer, &readOnlyClusterClient{ClusterServiceClient: client}, nil
+}
+
+func (c *readOnlyClient) NewClusterClientOrDie() (io.Closer, clusterpkg.ClusterServiceClient) {
+	return orDie(c.NewClusterClient())
+}
+
+func (c *readOnlyClient) NewRepoCredsClient() (io.Closer, repocredspkg.RepoCredsServiceClient, error) {
+	return nil, nil, unavailable("repository credentials")
+}
+
+func (c *readOnlyClient) NewRepoCredsClientOrDie() (io.Closer, repocredspkg.RepoCredsServiceClient) {
+	return orDie(c.NewRepoCredsClient())
+}
+
+func (c *readOnlyClient) NewCertClient() (io.Closer, certificatepkg.CertificateServiceClient, error) {
+	return nil, nil, unavailable("certificate")
+}
+
+func (c *readOnlyClient) NewCertClientOrDie() (io.Closer, certificatepkg.CertificateServiceClient) {
+	return orDie(c.NewCertClient())
+}
+
+func (c *readOnlyClient) NewGPGKeyClient() (io.Closer, gpgkeypkg.GPGKeyServiceClient, error) {
+	return nil, nil, unavailable("GPG key")
+}
+
+func (c *readOnlyClient) NewGPGKeyClientOrDie() (io.Closer, gpgkeypkg.GPGKeyServiceClient) {
+	return orDie(c.NewGPGKeyClient())
+}
+
+func (c *readOnlyClient) NewApplicationSetClient() (io.Closer, applicationsetpkg.ApplicationSetServiceClient, error) {
+	return nil, nil, unavailable("application set")
+}
+
+func (c *readOnlyClient) NewApplicationSetClientOrDie() (io.Closer, applicationsetpkg.ApplicationSetServiceClient) {
+	return orDie(c.NewApplicationSetClient())
+}
+
+func (c *readOnlyClient) NewSessionClient() (io.Closer, sessionpkg.SessionServiceClient, error) {
+	return nil, nil, unavailable("session")
+}
+
+func (c *readOnlyClient) NewSessionClientOrDie() (io.Closer, sessionpkg.SessionServiceClient) {
+	return orDie(c.NewSessionClient())
+}
+
+func (c *readOnlyClient) NewAccountClient() (io.Closer, accountpkg.AccountServiceClient, error) {
+	return nil, nil, unavailable("account")
+}
+
+func (c *readOnlyClient) NewAccountClientOrDie() (io.Closer, accountpkg.AccountServiceClient) {
+	return orDie(c.NewAccountClient())
+}
+
+// HTTPClient returns the authenticated HTTP client with a transport that only sends GET, HEAD and OPTIONS requests
+func (c *readOnlyClient) HTTPClient() (*http.Client, error) {
+	client, err := c.Client.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	readOnly := *client
+	readOnly.Transport = &readOnlyTransport{base: client.Transport}
+	return &readOnly, nil
+}
+
+// readOnlyTransport refuses requests whose method can write
+type readOnlyTransport struct {
+	base http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s requests are not allowed", ErrReadOnly, req.Method)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// readOnlyApplicationClient refuses application writes
+type readOnlyApplicationClient struct {
+	applicationpkg.ApplicationServiceClient
+}
+
+// Get refuses refreshes, which make the controller reconcile the application
+func (c *readOnlyApplicationClient) Get(ctx context.Context, in *applicationpkg.ApplicationQuery, opts ...grpc.CallOption) (*v1alpha1.Application, error) {
+	if in.GetRefresh() != "" {
+		return nil, ErrReadOnly
+	}
+	return c.ApplicationServiceClient.Get(ctx, in, opts...)
+}
+
+func (c *readOnlyApplicationClient) Create(context.Context, *applicationpkg.ApplicationCreateRequest, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) Update(context.Context, *applicationpkg.ApplicationUpdateRequest, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) UpdateSpec(context.Context, *applicationpkg.ApplicationUpdateSpecRequest, ...grpc.CallOption) (*v1alpha1.ApplicationSpec, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) Patch(context.Context, *applicationpkg.ApplicationPatchRequest, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) Delete(context.Context, *applicationpkg.ApplicationDeleteRequest, ...grpc.CallOption) (*applicationpkg.ApplicationResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) Sync(context.Context, *applicationpkg.ApplicationSyncRequest, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) Rollback(context.Context, *applicationpkg.ApplicationRollbackRequest, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) TerminateOperation(context.Context, *applicationpkg.OperationTerminateRequest, ...grpc.CallOption) (*applicationpkg.OperationTerminateResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) PatchResource(context.Context, *applicationpkg.ApplicationResourcePatchRequest, ...grpc.CallOption) (*applicationpkg.ApplicationResourceResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) RunResourceAction(context.Context, *applicationpkg.ResourceActionRunRequest, ...grpc.CallOption) (*applicationpkg.ApplicationResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyApplicationClient) DeleteResource(context.Context, *applicationpkg.ApplicationResourceDeleteRequest, ...grpc.CallOption) (*applicationpkg.ApplicationResponse, error) {
+	return nil, ErrReadOnly
+}
+
+// readOnlyProjectClient refuses project writes
+type readOnlyProjectClient struct {
+	projectpkg.ProjectServiceClient
+}
+
+func (c *readOnlyProjectClient) CreateToken(context.Context, *projectpkg.ProjectTokenCreateRequest, ...grpc.CallOption) (*projectpkg.ProjectTokenResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyProjectClient) DeleteToken(context.Context, *projectpkg.ProjectTokenDeleteRequest, ...grpc.CallOption) (*projectpkg.EmptyResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyProjectClient) Create(context.Context, *projectpkg.ProjectCreateRequest, ...grpc.CallOption) (*v1alpha1.AppProject, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyProjectClient) Update(context.Context, *projectpkg.ProjectUpdateRequest, ...grpc.CallOption) (*v1alpha1.AppProject, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyProjectClient) Delete(context.Context, *projectpkg.ProjectQuery, ...grpc.CallOption) (*projectpkg.EmptyResponse, error) {
+	return nil, ErrReadOnly
+}
+
+// readOnlyRepoClient refuses repository writes
+type readOnlyRepoClient struct {
+	repositorypkg.RepositoryServiceClient
+}
+
+func (c *readOnlyRepoClient) Create(context.Context, *repositorypkg.RepoCreateRequest, ...grpc.CallOption) (*v1alpha1.Repository, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) CreateRepository(context.Context, *repositorypkg.RepoCreateRequest, ...grpc.CallOption) (*v1alpha1.Repository, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) CreateWriteRepository(context.Context, *repositorypkg.RepoCreateRequest, ...grpc.CallOption) (*v1alpha1.Repository, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) Update(context.Context, *repositorypkg.RepoUpdateRequest, ...grpc.CallOption) (*v1alpha1.Repository, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) UpdateRepository(context.Context, *repositorypkg.RepoUpdateRequest, ...grpc.CallOption) (*v1alpha1.Repository, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) UpdateWriteRepository(context.Context, *repositorypkg.RepoUpdateRequest, ...grpc.CallOption) (*v1alpha1.Repository, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) Delete(context.Context, *repositorypkg.RepoQuery, ...grpc.CallOption) (*repositorypkg.RepoResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) DeleteRepository(context.Context, *repositorypkg.RepoQuery, ...grpc.CallOption) (*repositorypkg.RepoResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyRepoClient) DeleteWriteRepository(context.Context, *repositorypkg.RepoQuery, ...grpc.CallOption) (*repositorypkg.RepoResponse, error) {
+	return nil, ErrReadOnly
+}
+
+// readOnlyClusterClient refuses cluster writes
+type readOnlyClusterClient struct {
+	clusterpkg.ClusterServiceClient
+}
+
+func (c *readOnlyClusterClient) Create(context.Context, *clusterpkg.ClusterCreateRequest, ...grpc.CallOption) (*v1alpha1.Cluster, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClusterClient) Update(context.Context, *clusterpkg.ClusterUpdateRequest, ...grpc.CallOption) (*v1alpha1.Cluster, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClusterClient) Delete(context.Context, *clusterpkg.ClusterQuery, ...grpc.CallOption) (*clusterpkg.ClusterResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClusterClient) RotateAuth(context.Context, *clusterpkg.ClusterQuery, ...grpc.CallOption) (*clusterpkg.ClusterResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClusterClient) InvalidateCache(context.Context, *clusterpkg.ClusterQuery, ...grpc.CallOption) (*v1alpha1.Cluster, error) {
+	return nil, ErrReadOnly
+}
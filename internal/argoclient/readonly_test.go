@@ -0,0 +1,186 @@
+package argoclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"google.golang.org/grpc"
+)
+
+// fakeApplicationClient records the application reads that reach it
+type fakeApplicationClient struct {
+	applicationpkg.ApplicationServiceClient
+	gets int
+}
+
+func (c *fakeApplicationClient) Get(context.Context, *applicationpkg.ApplicationQuery, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	c.gets++
+	return &v1alpha1.Application{}, nil
+}
+
+// fakeClient hands out a fakeApplicationClient
+type fakeClient struct {
+	apiclient.Client
+	app *fakeApplicationClient
+}
+
+func (c *fakeClient) NewApplicationClient() (io.Closer, applicationpkg.ApplicationServiceClient, error) {
+	return io.NopCloser(nil), c.app, nil
+}
+
+func (c *fakeClient) HTTPClient() (*http.Client, error) {
+	return &http.Client{}, nil
+}
+
+// refusedMethods calls every method of a read-only service client except the given reads
+// Returns the methods that didn't fail with ErrReadOnly; a wrapped nil client makes unguarded methods panic
+func refusedMethods(client any, reads ...string) []string {
+	var unguarded []string
+	v := reflect.ValueOf(client)
+	for i := 0; i < v.NumMethod(); i++ {
+		name := v.Type().Method(i).Name
+		if slices.Contains(reads, name) {
+			continue
+		}
+
+		method := v.Method(i)
+		args := []reflect.Value{reflect.ValueOf(context.Background())}
+		if method.Type().NumIn() > 2 {
+			args = append(args, reflect.Zero(method.Type().In(1)))
+		}
+
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%v", r)
+				}
+			}()
+			results := method.Call(args)
+			if e, ok := results[len(results)-1].Interface().(error); ok {
+				return e
+			}
+			return nil
+		}()
+		if !errors.Is(err, ErrReadOnly) {
+			unguarded = append(unguarded, name)
+		}
+	}
+	return unguarded
+}
+
+var _ = Describe("ReadOnlyClient", func() {
+	It("should refuse every application write", func() {
+		Expect(refusedMethods(&readOnlyApplicationClient{},
+			"List", "ListResourceEvents", "Watch", "Get", "GetApplicationSyncWindows", "RevisionMetadata", "RevisionChartDetails",
+			"GetManifests", "GetManifestsWithFiles", "ManagedResources", "ResourceTree", "WatchResourceTree", "GetResource",
+			"ListResourceActions", "PodLogs", "ListLinks", "ListResourceLinks",
+		)).To(BeEmpty())
+	})
+
+	It("should refuse every project write", func() {
+		Expect(refusedMethods(&readOnlyProjectClient{},
+			"List", "GetDetailedProject", "Get", "GetGlobalProjects", "ListEvents", "GetSyncWindowsState", "ListLinks",
+		)).To(BeEmpty())
+	})
+
+	It("should refuse every repository write", func() {
+		Expect(refusedMethods(&readOnlyRepoClient{},
+			"List", "Get", "GetWrite", "ListRepositories", "ListWriteRepositories", "ListRefs", "ListApps", "GetAppDetails",
+			"GetHelmCharts", "ValidateAccess", "ValidateWriteAccess",
+		)).To(BeEmpty())
+	})
+
+	It("should refuse every cluster write", func() {
+		Expect(refusedMethods(&readOnlyClusterClient{}, "List", "Get")).To(BeEmpty())
+	})
+
+	Describe("NewApplicationClient", func() {
+		var app *fakeApplicationClient
+		var client apiclient.Client
+
+		BeforeEach(func() {
+			app = &fakeApplicationClient{}
+			client = NewReadOnlyClient(&fakeClient{app: app})
+		})
+
+		It("should pass reads through", func() {
+			_, appClient, err := client.NewApplicationClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			name := "guestbook"
+			_, err = appClient.Get(context.Background(), &applicationpkg.ApplicationQuery{Name: &name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.gets).To(Equal(1))
+		})
+
+		It("should refuse refreshes", func() {
+			_, appClient, err := client.NewApplicationClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			name, refresh := "guestbook", string(v1alpha1.RefreshTypeHard)
+			_, err = appClient.Get(context.Background(), &applicationpkg.ApplicationQuery{Name: &name, Refresh: &refresh})
+			Expect(err).To(MatchError(ErrReadOnly))
+			Expect(app.gets).To(BeZero())
+		})
+
+		It("should refuse writes", func() {
+			_, appClient, err := client.NewApplicationClient()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = appClient.Sync(context.Background(), &applicationpkg.ApplicationSyncRequest{})
+			Expect(err).To(MatchError(ErrReadOnly))
+		})
+	})
+
+	It("should refuse services that are only needed to write", func() {
+		client := NewReadOnlyClient(&fakeClient{})
+
+		_, _, err := client.NewAccountClient()
+		Expect(err).To(MatchError(ErrReadOnly))
+		_, _, err = client.NewRepoCredsClient()
+		Expect(err).To(MatchError(ContainSubstring("repository credentials service is not available")))
+	})
+
+	It("should only send reads through the HTTP client", func() {
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+		}))
+		defer server.Close()
+
+		httpClient, err := NewReadOnlyClient(&fakeClient{}).HTTPClient()
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := httpClient.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+
+		_, err = httpClient.Post(server.URL, "application/json", nil)
+		Expect(err).To(MatchError(ErrReadOnly))
+		req, err := http.NewRequest(http.MethodDelete, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = httpClient.Do(req)
+		Expect(err).To(MatchError(ErrReadOnly))
+
+		Expect(methods).To(Equal([]string{http.MethodGet}))
+	})
+
+	It("should be used by NewClient in read-only mode", func() {
+		client, err := NewClient(Config{Server: "argocd.example.com:443", AuthToken: "test-token", ReadOnly: true})
+		if err == nil {
+			Expect(client.Client).To(BeAssignableToTypeOf(&readOnlyClient{}))
+		}
+	})
+})
@@ -0,0 +1,27 @@
+package argoclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	sessionpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/session"
+)
+
+// CurrentUser returns the name of the Argo CD user the client is authenticated as
+func CurrentUser(ctx context.Context, client apiclient.Client) (string, error) {
+	conn, sessionClient, err := client.NewSessionClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to create session client: %w", err)
+	}
+	defer conn.Close()
+
+	info, err := sessionClient.GetUserInfo(ctx, &sessionpkg.GetUserInfoRequest{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get user info: %w", err)
+	}
+	if !info.LoggedIn {
+		return "", fmt.Errorf("argo cd token is not logged in")
+	}
+	return info.Username, nil
+}
@@ -0,0 +1,418 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sethvargo/go-envconfig"
+)
+
+// MinKeyLength is the shortest audit key accepted
+const MinKeyLength = 32
+
+// Config defines where the audit log is written and the key its records are signed with
+// The head file records the last record written, so records removed from the end of the log are detected
+// It has no default, since it must be kept outside the log's directory
+type Config struct {
+	File string `env:"MCP_AUDIT_LOG,default=/tmp/bw-mcp/audit.jsonl"`
+	Head string `env:"MCP_AUDIT_HEAD"`
+	Key  string `env:"MCP_AUDIT_KEY"`
+}
+
+// NewConfigFromEnv loads the audit configuration from environment variables
+func NewConfigFromEnv(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to process environment variables: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Phases of an audited tool call
+const (
+	// PhaseIntent is recorded before a call runs, with its arguments
+	PhaseIntent = "intent"
+	// PhaseCompletion is recorded after a call returns, with its result
+	PhaseCompletion = "completion"
+)
+
+// Record is one phase of an audited tool call
+// Hash is an HMAC over every other field, including PrevHash, so records can't be edited, removed or rewritten without the key
+type Record struct {
+	Seq       int64           `json:"seq"`
+	Time      string          `json:"time"`
+	Phase     string          `json:"phase"`
+	Intent    int64           `json:"intent,omitempty"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Client    ClientInfo      `json:"client"`
+	SessionID string          `json:"sessionId"`
+	ArgoUser  string          `json:"argoUser"`
+	Result    *Result         `json:"result,omitempty"`
+	PrevHash  string          `json:"prevHash"`
+	Hash      string          `json:"hash"`
+}
+
+// ClientInfo identifies the MCP client that made a call
+type ClientInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Result is the outcome of an audited call
+type Result struct {
+	IsError bool            `json:"isError"`
+	Error   string          `json:"error,omitempty"`
+	Output  json.RawMessage `json:"output,omitempty"`
+}
+
+// Head is the position of the last record written to an audit log
+// Signature is an HMAC over Seq and Hash, so a head can't be rewritten to match a truncated log without the key
+type Head struct {
+	Seq       int64  `json:"seq"`
+	Hash      string `json:"hash"`
+	Signature string `json:"signature"`
+}
+
+// Log is an append-only, hash-chained JSONL audit log
+// Unlike the operational log it is never truncated, and records are synced to disk before a call returns
+type Log struct {
+	mu       sync.Mutex
+	file     *os.File
+	headPath string
+	key      []byte
+	seq      int64
+	lastHash string
+	now      func() time.Time
+}
+
+// Open opens the audit log for appending, continuing the hash chain from its last record
+// It refuses logs that weren't signed with key or that no longer reach their head
+func Open(path, headPath string, key []byte) (*Log, error) {
+	if len(key) < MinKeyLength {
+		return nil, fmt.Errorf("audit key must be at least %d bytes", MinKeyLength)
+	}
+	if err := checkHeadPath(path, headPath); err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{filepath.Dir(path), filepath.Dir(headPath)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	last, err := lastRecord(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to continue audit log %s, run audit verify to inspect it: %w", path, err)
+	}
+	head, err := ReadHead(headPath, key)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to continue audit log %s, run audit verify to inspect it: %w", path, err)
+	}
+	if err := checkContinuation(last, head, key); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to continue audit log %s, run audit verify to inspect it: %w", path, err)
+	}
+
+	l := &Log{file: file, headPath: headPath, key: key, now: time.Now}
+	if last != nil {
+		l.seq = last.Seq
+		l.lastHash = last.Hash
+	}
+	return l, nil
+}
+
+// Close closes the audit log
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Append completes the record's sequence number, time and hash chain and writes it
+func (l *Log) Append(record Record) (Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record.Seq = l.seq + 1
+	record.Time = l.now().UTC().Format(time.RFC3339Nano)
+	record.PrevHash = l.lastHash
+	hash, err := recordHash(record, l.key)
+	if err != nil {
+		return Record{}, err
+	}
+	record.Hash = hash
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return Record{}, fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return Record{}, fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	l.seq = record.Seq
+	l.lastHash = record.Hash
+	if err := writeHead(l.headPath, Head{Seq: record.Seq, Hash: record.Hash}, l.key); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// recordHash computes the HMAC of a record over all fields except Hash
+func recordHash(record Record, key []byte) (string, error) {
+	record.Hash = ""
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// checkContinuation checks that the last record of a log is signed with key and reaches its head
+// A head behind the last record is accepted, since the server may have stopped between writing the record and the head
+func checkContinuation(last *Record, head *Head, key []byte) error {
+	var lastSeq int64
+	if last != nil {
+		hash, err := recordHash(*last, key)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal([]byte(hash), []byte(last.Hash)) {
+			return fmt.Errorf("last record is not signed with this audit key")
+		}
+		lastSeq = last.Seq
+	}
+
+	switch {
+	case head == nil && last != nil:
+		return fmt.Errorf("audit log has no head")
+	case head == nil:
+		return nil
+	case head.Seq > lastSeq:
+		return fmt.Errorf("audit log ends at record %d but its head is record %d, records were removed from the end", lastSeq, head.Seq)
+	case head.Seq == lastSeq && last != nil && head.Hash != last.Hash:
+		return fmt.Errorf("last record does not match the head")
+	}
+	return nil
+}
+
+// checkHeadPath makes sure the head isn't kept in the log's directory, where whoever can truncate the log could replace it too
+func checkHeadPath(path, headPath string) error {
+	if headPath == "" {
+		return fmt.Errorf("audit log head file is required")
+	}
+	logDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("failed to resolve audit log directory: %w", err)
+	}
+	headDir, err := filepath.Abs(filepath.Dir(headPath))
+	if err != nil {
+		return fmt.Errorf("failed to resolve audit log head directory: %w", err)
+	}
+	if logDir == headDir {
+		return fmt.Errorf("audit log head must be kept outside the audit log's directory %s", logDir)
+	}
+	return nil
+}
+
+// headSignature computes the HMAC of a head over its position in the log
+func headSignature(head Head, key []byte) (string, error) {
+	head.Signature = ""
+	data, err := json.Marshal(head)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit log head: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ReadHead reads the head of an audit log and checks it was signed with key, or returns nil if it has none yet
+func ReadHead(path string, key []byte) (*Head, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log head: %w", err)
+	}
+	var head Head
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("failed to parse audit log head: %w", err)
+	}
+	signature, err := headSignature(head, key)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(head.Signature)) {
+		return nil, fmt.Errorf("audit log head is not signed with this audit key")
+	}
+	return &head, nil
+}
+
+// writeHead signs and replaces the head of an audit log, so a crash leaves either the old or the new head
+func writeHead(path string, head Head, key []byte) error {
+	signature, err := headSignature(head, key)
+	if err != nil {
+		return err
+	}
+	head.Signature = signature
+	data, err := json.Marshal(head)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log head: %w", err)
+	}
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log head: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log head: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync audit log head: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log head: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write audit log head: %w", err)
+	}
+	return nil
+}
+
+// lastRecord reads the last record of an audit log, or nil if it is empty
+func lastRecord(file *os.File) (*Record, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var last []byte
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if err == io.EOF {
+				return nil, fmt.Errorf("last record is incomplete")
+			}
+			last = line
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+
+	var record Record
+	if err := json.Unmarshal(last, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse last record: %w", err)
+	}
+	return &record, nil
+}
+
+// Verify checks the hash chain of an audit log against key and its head, and returns the number of records
+// The error names the first line that was edited, removed, reordered or inserted, or reports records removed from the end
+// A nil head means the log has none, which is only valid while it is empty
+func Verify(r io.Reader, key []byte, head *Head) (int64, error) {
+	reader := bufio.NewReader(r)
+	var (
+		count    int64
+		lastHash string
+	)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return count, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			record, verifyErr := verifyRecord(line, key, count+1, lastHash)
+			if verifyErr != nil {
+				return count, fmt.Errorf("line %d: %w", lineNo, verifyErr)
+			}
+			if head != nil && record.Seq == head.Seq && record.Hash != head.Hash {
+				return count, fmt.Errorf("line %d: record %d does not match the head", lineNo, record.Seq)
+			}
+			count = record.Seq
+			lastHash = record.Hash
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	switch {
+	case head == nil && count > 0:
+		return count, fmt.Errorf("audit log has no head")
+	case head != nil && head.Seq > count:
+		return count, fmt.Errorf("audit log ends at record %d but its head is record %d, records were removed from the end", count, head.Seq)
+	}
+	return count, nil
+}
+
+// verifyRecord checks a single record against its position in the chain
+func verifyRecord(line []byte, key []byte, seq int64, prevHash string) (*Record, error) {
+	var record Record
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, fmt.Errorf("invalid record: %w", err)
+	}
+	if record.Seq != seq {
+		return nil, fmt.Errorf("expected record %d, found record %d", seq, record.Seq)
+	}
+	if record.PrevHash != prevHash {
+		return nil, fmt.Errorf("record %d does not link to the previous record", record.Seq)
+	}
+	hash, err := recordHash(record, key)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(hash), []byte(record.Hash)) {
+		return nil, fmt.Errorf("record %d was modified, its hash does not match its content", record.Seq)
+	}
+	return &record, nil
+}
+
+// VerifyFile checks the hash chain of the audit log at path against key and the head at headPath
+func VerifyFile(path, headPath string, key []byte) (int64, error) {
+	head, err := ReadHead(headPath, key)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+	return Verify(file, key, head)
+}
@@ -0,0 +1,243 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+var _ = Describe("Audit log", func() {
+	var path, headPath string
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		path = filepath.Join(dir, "audit", "audit.jsonl")
+		headPath = filepath.Join(dir, "head", "audit.head")
+	})
+
+	appendRecords := func(tools ...string) {
+		auditLog, err := Open(path, headPath, testKey)
+		Expect(err).NotTo(HaveOccurred())
+		defer auditLog.Close()
+		for _, tool := range tools {
+			_, err := auditLog.Append(Record{Tool: tool, Arguments: json.RawMessage(`{"name": "guestbook"}`), ArgoUser: "admin"})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+
+	readLines := func() []string {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	writeLines := func(lines []string) {
+		Expect(os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)).To(Succeed())
+	}
+
+	Describe("Append", func() {
+		It("should refuse a short key", func() {
+			_, err := Open(path, headPath, []byte("secret"))
+			Expect(err).To(MatchError("audit key must be at least 32 bytes"))
+		})
+
+		It("should refuse a head kept in the log's directory or none at all", func() {
+			_, err := Open(path, filepath.Join(filepath.Dir(path), "audit.head"), testKey)
+			Expect(err).To(MatchError(HavePrefix("audit log head must be kept outside the audit log's directory")))
+
+			_, err = Open(path, "", testKey)
+			Expect(err).To(MatchError("audit log head file is required"))
+		})
+
+		It("should chain records", func() {
+			auditLog, err := Open(path, headPath, testKey)
+			Expect(err).NotTo(HaveOccurred())
+			defer auditLog.Close()
+			auditLog.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+			first, err := auditLog.Append(Record{Tool: "argocd_sync_application"})
+			Expect(err).NotTo(HaveOccurred())
+			second, err := auditLog.Append(Record{Tool: "argocd_delete_application"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first.Seq).To(Equal(int64(1)))
+			Expect(first.Time).To(Equal("2026-01-02T03:04:05Z"))
+			Expect(first.PrevHash).To(BeEmpty())
+			Expect(first.Hash).To(HaveLen(64))
+			Expect(second.Seq).To(Equal(int64(2)))
+			Expect(second.PrevHash).To(Equal(first.Hash))
+
+			head, err := ReadHead(headPath, testKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(head.Seq).To(Equal(int64(2)))
+			Expect(head.Hash).To(Equal(second.Hash))
+			Expect(head.Signature).To(HaveLen(64))
+		})
+
+		It("should continue the chain after reopening instead of truncating", func() {
+			appendRecords("argocd_sync_application")
+			appendRecords("argocd_rollback_application", "argocd_delete_application")
+
+			lines := readLines()
+			Expect(lines).To(HaveLen(3))
+			count, err := VerifyFile(path, headPath, testKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(3)))
+		})
+
+		It("should refuse to continue a log signed with another key", func() {
+			appendRecords("argocd_sync_application")
+
+			_, err := Open(path, headPath, []byte("fedcba9876543210fedcba9876543210"))
+			Expect(err).To(MatchError(ContainSubstring("not signed with this audit key")))
+		})
+
+		It("should refuse to continue a log with records removed from the end", func() {
+			appendRecords("argocd_sync_application", "argocd_delete_application")
+			writeLines(readLines()[:1])
+
+			_, err := Open(path, headPath, testKey)
+			Expect(err).To(MatchError(ContainSubstring("audit log ends at record 1 but its head is record 2")))
+		})
+
+		It("should refuse to continue a log whose head was rewritten without the key", func() {
+			appendRecords("argocd_sync_application", "argocd_delete_application")
+			lines := readLines()
+			var first Record
+			Expect(json.Unmarshal([]byte(lines[0]), &first)).To(Succeed())
+			writeLines(lines[:1])
+			Expect(os.WriteFile(headPath, []byte(fmt.Sprintf(`{"seq":1,"hash":%q}`, first.Hash)), 0600)).To(Succeed())
+
+			_, err := Open(path, headPath, testKey)
+			Expect(err).To(MatchError(ContainSubstring("audit log head is not signed with this audit key")))
+		})
+
+		It("should refuse to continue a log whose head is missing", func() {
+			appendRecords("argocd_sync_application")
+			Expect(os.Remove(headPath)).To(Succeed())
+
+			_, err := Open(path, headPath, testKey)
+			Expect(err).To(MatchError(ContainSubstring("audit log has no head")))
+		})
+
+		It("should refuse to continue a log whose last record is damaged", func() {
+			appendRecords("argocd_sync_application")
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = file.WriteString(`{"seq": 2, "tool"`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+
+			_, err = Open(path, headPath, testKey)
+			Expect(err).To(MatchError(ContainSubstring("last record is incomplete")))
+		})
+	})
+
+	Describe("Verify", func() {
+		BeforeEach(func() {
+			appendRecords("argocd_sync_application", "argocd_rollback_application", "argocd_delete_application")
+		})
+
+		It("should accept an empty log", func() {
+			count, err := Verify(bytes.NewReader(nil), testKey, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+
+		It("should detect an edited record", func() {
+			lines := readLines()
+			lines[1] = strings.Replace(lines[1], `"argoUser":"admin"`, `"argoUser":"someone"`, 1)
+			writeLines(lines)
+
+			count, err := VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("line 2: record 2 was modified, its hash does not match its content"))
+			Expect(count).To(Equal(int64(1)))
+		})
+
+		It("should detect a removed record", func() {
+			lines := readLines()
+			writeLines([]string{lines[0], lines[2]})
+
+			_, err := VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("line 2: expected record 2, found record 3"))
+		})
+
+		It("should detect records removed from the end", func() {
+			writeLines(readLines()[:2])
+
+			count, err := VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("audit log ends at record 2 but its head is record 3, records were removed from the end"))
+			Expect(count).To(Equal(int64(2)))
+		})
+
+		It("should detect records rewritten without the key", func() {
+			lines := readLines()
+			var record Record
+			Expect(json.Unmarshal([]byte(lines[0]), &record)).To(Succeed())
+			record.ArgoUser = "someone"
+			record.Hash, _ = recordHash(record, []byte("fedcba9876543210fedcba9876543210"))
+			data, err := json.Marshal(record)
+			Expect(err).NotTo(HaveOccurred())
+			lines[0] = string(data)
+			writeLines(lines)
+
+			_, err = VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("line 1: record 1 was modified, its hash does not match its content"))
+		})
+
+		It("should detect a head rewritten to match a truncated log", func() {
+			lines := readLines()
+			var second Record
+			Expect(json.Unmarshal([]byte(lines[1]), &second)).To(Succeed())
+			writeLines(lines[:2])
+			Expect(os.WriteFile(headPath, []byte(fmt.Sprintf(`{"seq":2,"hash":%q,"signature":%q}`, second.Hash, strings.Repeat("0", 64))), 0600)).To(Succeed())
+
+			_, err := VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("audit log head is not signed with this audit key"))
+		})
+
+		It("should detect a missing head", func() {
+			Expect(os.Remove(headPath)).To(Succeed())
+
+			_, err := VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("audit log has no head"))
+		})
+
+		It("should detect a rewritten record that no longer links to the chain", func() {
+			lines := readLines()
+			var record Record
+			Expect(json.Unmarshal([]byte(lines[1]), &record)).To(Succeed())
+			record.PrevHash = strings.Repeat("0", 64)
+			record.Hash, _ = recordHash(record, testKey)
+			data, err := json.Marshal(record)
+			Expect(err).NotTo(HaveOccurred())
+			lines[1] = string(data)
+			writeLines(lines)
+
+			_, err = VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError("line 2: record 2 does not link to the previous record"))
+		})
+
+		It("should detect a line that isn't a record", func() {
+			lines := readLines()
+			writeLines(append(lines, "not json"))
+
+			_, err := VerifyFile(path, headPath, testKey)
+			Expect(err).To(MatchError(ContainSubstring("line 4: invalid record")))
+		})
+
+		It("should fail on a missing file", func() {
+			_, err := VerifyFile(filepath.Join(GinkgoT().TempDir(), "missing.jsonl"), headPath, testKey)
+			Expect(err).To(MatchError(ContainSubstring("failed to open audit log")))
+		})
+	})
+})
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"template_cli/internal/log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// UnknownUser is recorded when the Argo CD user can't be determined
+const UnknownUser = "unknown"
+
+// NewMiddleware returns MCP middleware that records every call to an audited tool before and after it runs
+// Middleware added later wraps it, so it must be added after the policy middleware, to record calls the policy refuses,
+// and before the planner middleware, whose applied plans are dispatched through it
+func NewMiddleware(auditLog *Log, audited func(tool string) bool, argoUser func(ctx context.Context) (string, error)) mcp.Middleware {
+	users := &userResolver{resolve: argoUser}
+	processSession := newProcessSessionID()
+
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil || !audited(callReq.Params.Name) {
+				return next(ctx, method, req)
+			}
+
+			record := Record{
+				Phase:     PhaseIntent,
+				Tool:      callReq.Params.Name,
+				Arguments: callReq.Params.Arguments,
+				SessionID: processSession,
+				ArgoUser:  users.user(ctx),
+			}
+			if callReq.Session != nil {
+				if id := callReq.Session.ID(); id != "" {
+					record.SessionID = id
+				}
+				if params := callReq.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+					record.Client = ClientInfo{Name: params.ClientInfo.Name, Version: params.ClientInfo.Version}
+				}
+			}
+
+			// A call that can't be recorded isn't run
+			intent, appendErr := auditLog.Append(record)
+			if appendErr != nil {
+				log.Logger().With("component", "audit").Errorw("Failed to write audit record, refusing the call", "tool", record.Tool, "error", appendErr)
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s was not run because it could not be recorded in the audit log: %v", record.Tool, appendErr)}},
+				}, nil
+			}
+
+			result, err := next(ctx, method, req)
+
+			outcome := callResult(result, err)
+			record.Phase = PhaseCompletion
+			record.Intent = intent.Seq
+			record.Arguments = nil
+			record.Result = &outcome
+			if _, appendErr := auditLog.Append(record); appendErr != nil {
+				log.Logger().With("component", "audit").Errorw("Failed to write audit completion record", "tool", record.Tool, "intent", intent.Seq, "error", appendErr)
+			}
+			return result, err
+		}
+	}
+}
+
+// callResult summarizes the outcome of a tool call for the audit log
+func callResult(result mcp.Result, err error) Result {
+	if err != nil {
+		return Result{IsError: true, Error: err.Error()}
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	if !ok || toolResult == nil {
+		return Result{}
+	}
+
+	out := Result{IsError: toolResult.IsError}
+	if toolResult.IsError {
+		var messages []string
+		for _, content := range toolResult.Content {
+			if text, ok := content.(*mcp.TextContent); ok {
+				messages = append(messages, text.Text)
+			}
+		}
+		out.Error = strings.Join(messages, "\n")
+	}
+	if toolResult.StructuredContent != nil {
+		if output, err := json.Marshal(toolResult.StructuredContent); err == nil {
+			out.Output = output
+		}
+	}
+	return out
+}
+
+// userResolver looks up the Argo CD user once and remembers it
+// Failures aren't remembered, so a temporary outage doesn't leave every later record without a user
+type userResolver struct {
+	mu      sync.Mutex
+	resolve func(ctx context.Context) (string, error)
+	name    string
+}
+
+// user returns the Argo CD user, or UnknownUser if it can't be determined
+func (u *userResolver) user(ctx context.Context) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.name != "" {
+		return u.name
+	}
+	name, err := u.resolve(ctx)
+	if err != nil || name == "" {
+		log.Logger().With("component", "audit").Warnw("Failed to determine the Argo CD user", "error", err)
+		return UnknownUser
+	}
+	u.name = name
+	return name
+}
+
+// newProcessSessionID identifies the session of transports without session IDs, like stdio, where there is one per process
+func newProcessSessionID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return "process-" + hex.EncodeToString(id)
+}
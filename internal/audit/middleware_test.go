@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	var (
+		path      string
+		headPath  string
+		auditLog  *Log
+		userCalls int
+		userErr   error
+	)
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		path = filepath.Join(dir, "audit.jsonl")
+		headPath = filepath.Join(dir, "head", "audit.head")
+		var err error
+		auditLog, err = Open(path, headPath, testKey)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(auditLog.Close)
+		userCalls = 0
+		userErr = nil
+	})
+
+	call := func(tool string, result mcp.Result, err error) {
+		middleware := NewMiddleware(auditLog, func(tool string) bool { return strings.HasPrefix(tool, "write_") }, func(context.Context) (string, error) {
+			userCalls++
+			if userErr != nil {
+				return "", userErr
+			}
+			return "admin", nil
+		})
+		next := func(context.Context, string, mcp.Request) (mcp.Result, error) {
+			return result, err
+		}
+		gotResult, gotErr := middleware(next)(context.Background(), "tools/call", &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: tool, Arguments: json.RawMessage(`{"name":"guestbook"}`)},
+		})
+		Expect(gotResult == result).To(BeTrue(), "the result should be passed through")
+		Expect(gotErr == err).To(BeTrue(), "the error should be passed through")
+	}
+
+	records := func() []Record {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		var out []Record
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			var record Record
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			out = append(out, record)
+		}
+		return out
+	}
+
+	It("should record audited tool calls before and after they run", func() {
+		call("write_sync", &mcp.CallToolResult{StructuredContent: map[string]any{"started": true}}, nil)
+
+		written := records()
+		Expect(written).To(HaveLen(2))
+		Expect(written[0].Phase).To(Equal(PhaseIntent))
+		Expect(written[0].Tool).To(Equal("write_sync"))
+		Expect(string(written[0].Arguments)).To(Equal(`{"name":"guestbook"}`))
+		Expect(written[0].ArgoUser).To(Equal("admin"))
+		Expect(written[0].SessionID).To(HavePrefix("process-"))
+		Expect(written[0].Result == nil).To(BeTrue())
+
+		Expect(written[1].Phase).To(Equal(PhaseCompletion))
+		Expect(written[1].Intent).To(Equal(written[0].Seq))
+		Expect(written[1].Tool).To(Equal("write_sync"))
+		Expect(written[1].Arguments).To(BeEmpty())
+		Expect(written[1].SessionID).To(Equal(written[0].SessionID))
+		Expect(*written[1].Result).To(Equal(Result{Output: json.RawMessage(`{"started":true}`)}))
+	})
+
+	It("should skip other tools", func() {
+		call("read_application", &mcp.CallToolResult{}, nil)
+		Expect(records()).To(BeEmpty())
+	})
+
+	It("should record errors", func() {
+		call("write_delete", &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "policy denied"}}}, nil)
+		call("write_delete", nil, fmt.Errorf("connection lost"))
+
+		written := records()
+		Expect(written).To(HaveLen(4))
+		Expect(*written[1].Result).To(Equal(Result{IsError: true, Error: "policy denied"}))
+		Expect(*written[3].Result).To(Equal(Result{IsError: true, Error: "connection lost"}))
+
+		count, err := VerifyFile(path, headPath, testKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(4)))
+	})
+
+	It("should refuse calls that can't be recorded", func() {
+		closed, err := Open(filepath.Join(GinkgoT().TempDir(), "closed.jsonl"), filepath.Join(GinkgoT().TempDir(), "closed.head"), testKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closed.Close()).To(Succeed())
+		middleware := NewMiddleware(closed, func(string) bool { return true }, func(context.Context) (string, error) { return "admin", nil })
+		dispatched := false
+		next := func(context.Context, string, mcp.Request) (mcp.Result, error) {
+			dispatched = true
+			return &mcp.CallToolResult{}, nil
+		}
+
+		result, callErr := middleware(next)(context.Background(), "tools/call", &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: "write_delete", Arguments: json.RawMessage(`{"name":"guestbook"}`)},
+		})
+		Expect(callErr).NotTo(HaveOccurred())
+		Expect(dispatched).To(BeFalse())
+		toolResult, ok := result.(*mcp.CallToolResult)
+		Expect(ok).To(BeTrue())
+		Expect(toolResult.IsError).To(BeTrue())
+		Expect(toolResult.Content[0].(*mcp.TextContent).Text).To(HavePrefix("write_delete was not run because it could not be recorded in the audit log"))
+	})
+
+	It("should look up the Argo CD user once it succeeds", func() {
+		userErr = fmt.Errorf("unavailable")
+		call("write_sync", &mcp.CallToolResult{}, nil)
+		userErr = nil
+		call("write_sync", &mcp.CallToolResult{}, nil)
+		call("write_sync", &mcp.CallToolResult{}, nil)
+
+		written := records()
+		Expect(written[0].ArgoUser).To(Equal(UnknownUser))
+		Expect(written[1].ArgoUser).To(Equal(UnknownUser))
+		Expect(written[2].ArgoUser).To(Equal("admin"))
+		Expect(written[4].ArgoUser).To(Equal("admin"))
+	})
+})
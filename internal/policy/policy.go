@@ -0,0 +1,219 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/argoproj/argo-cd/v2/util/glob"
+	"github.com/sethvargo/go-envconfig"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ActionAllow lets a tool call through
+	ActionAllow = "allow"
+
+	// ActionDeny refuses a tool call
+	ActionDeny = "deny"
+
+	// DefaultRuleName names the decision taken when no rule matches
+	DefaultRuleName = "defaultAction"
+)
+
+// Config defines where the policy is loaded from
+type Config struct {
+	File string `env:"MCP_POLICY_FILE"`
+}
+
+// NewConfigFromEnv loads the policy configuration from environment variables
+func NewConfigFromEnv(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to process environment variables: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Policy restricts which tools may act on which projects, clusters and namespaces
+// Rules are evaluated in order and the first matching rule decides; DefaultAction applies if none match
+type Policy struct {
+	DefaultAction string `json:"defaultAction,omitempty"`
+	Rules         []Rule `json:"rules"`
+}
+
+// Rule allows or denies tools for targets matching its glob patterns
+// An empty pattern list matches anything, including calls without a target, such as listing applications
+type Rule struct {
+	Name       string   `json:"name"`
+	Action     string   `json:"action"`
+	Tools      []string `json:"tools"`
+	Projects   []string `json:"projects,omitempty"`
+	Clusters   []string `json:"clusters,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Target is what a tool call acts on
+type Target struct {
+	Project       string `json:"project,omitempty"`
+	ClusterServer string `json:"clusterServer,omitempty"`
+	ClusterName   string `json:"clusterName,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+}
+
+// DeniedError is returned when the policy refuses a tool call
+type DeniedError struct {
+	Tool   string `json:"tool"`
+	Rule   string `json:"rule"`
+	Target Target `json:"target"`
+}
+
+func (e *DeniedError) Error() string {
+	if e.Rule == DefaultRuleName {
+		return fmt.Sprintf("policy denied %s on %s: no rule matched and the default action is deny", e.Tool, e.Target)
+	}
+	return fmt.Sprintf("policy denied %s on %s: matched rule %q", e.Tool, e.Target, e.Rule)
+}
+
+// String describes a target for error messages
+func (t Target) String() string {
+	var parts []string
+	if t.Project != "" {
+		parts = append(parts, "project "+t.Project)
+	}
+	if cluster := t.ClusterName; cluster != "" || t.ClusterServer != "" {
+		if cluster == "" {
+			cluster = t.ClusterServer
+		}
+		parts = append(parts, "cluster "+cluster)
+	}
+	if t.Namespace != "" {
+		parts = append(parts, "namespace "+t.Namespace)
+	}
+	if len(parts) == 0 {
+		return "no target"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Load reads and validates a policy file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a YAML or JSON policy
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if p.DefaultAction == "" {
+		p.DefaultAction = ActionDeny
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// validate checks that actions are known, rule names are unique and patterns compile
+func (p *Policy) validate() error {
+	if p.DefaultAction != ActionAllow && p.DefaultAction != ActionDeny {
+		return fmt.Errorf("invalid defaultAction %q, expected %q or %q", p.DefaultAction, ActionAllow, ActionDeny)
+	}
+
+	names := make(map[string]bool, len(p.Rules))
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %q is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Action != ActionAllow && rule.Action != ActionDeny {
+			return fmt.Errorf("rule %q has invalid action %q, expected %q or %q", rule.Name, rule.Action, ActionAllow, ActionDeny)
+		}
+		if len(rule.Tools) == 0 {
+			return fmt.Errorf("rule %q lists no tools", rule.Name)
+		}
+		for _, patterns := range [][]string{rule.Tools, rule.Projects, rule.Clusters, rule.Namespaces} {
+			for _, pattern := range patterns {
+				if _, err := glob.MatchWithError(pattern, ""); err != nil {
+					return fmt.Errorf("rule %q has invalid pattern %q: %w", rule.Name, pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns a *DeniedError if the policy refuses the tool call
+func (p *Policy) Check(tool string, target Target) error {
+	for _, rule := range p.Rules {
+		if !rule.matches(tool, target) {
+			continue
+		}
+		if rule.Action == ActionDeny {
+			return &DeniedError{Tool: tool, Rule: rule.Name, Target: target}
+		}
+		return nil
+	}
+
+	if p.DefaultAction == ActionDeny {
+		return &DeniedError{Tool: tool, Rule: DefaultRuleName, Target: target}
+	}
+	return nil
+}
+
+// NeedsTarget reports whether the decision for the tool can depend on the target
+// Callers can skip looking the target up when it can't, e.g. when an unscoped rule for the tool comes first
+func (p *Policy) NeedsTarget(tool string) bool {
+	for _, rule := range p.Rules {
+		if matchAny(rule.Tools, tool) {
+			return rule.scoped()
+		}
+	}
+	return false
+}
+
+// scoped reports whether the rule restricts projects, clusters or namespaces
+func (r Rule) scoped() bool {
+	return len(r.Projects) > 0 || len(r.Clusters) > 0 || len(r.Namespaces) > 0
+}
+
+// matches reports whether the rule applies to a tool call
+func (r Rule) matches(tool string, target Target) bool {
+	if !matchAny(r.Tools, tool) {
+		return false
+	}
+	if len(r.Projects) > 0 && !matchAny(r.Projects, target.Project) {
+		return false
+	}
+	if len(r.Clusters) > 0 && !matchAny(r.Clusters, target.ClusterServer) && !matchAny(r.Clusters, target.ClusterName) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !matchAny(r.Namespaces, target.Namespace) {
+		return false
+	}
+	return true
+}
+
+// matchAny reports whether a non-empty value matches any of the glob patterns
+func matchAny(patterns []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if glob.Match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const examplePolicy = `
+defaultAction: deny
+rules:
+  - name: read-anything
+    action: allow
+    tools: ["argocd_get_*", "argocd_list_*"]
+  - name: no-prod-syncs
+    action: deny
+    tools: ["argocd_sync_application"]
+    clusters: ["*prod*"]
+  - name: team-syncs
+    action: allow
+    tools: ["argocd_sync_application"]
+    projects: ["team-*"]
+`
+
+var _ = Describe("Policy", func() {
+	Describe("Parse", func() {
+		It("should default to deny", func() {
+			p, err := Parse([]byte(`rules: []`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.DefaultAction).To(Equal(ActionDeny))
+		})
+
+		DescribeTable("invalid policies",
+			func(data, message string) {
+				_, err := Parse([]byte(data))
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("unknown default action", `defaultAction: maybe`, `invalid defaultAction "maybe"`),
+			Entry("unnamed rule", `rules: [{action: allow, tools: ["*"]}]`, "rule 1 has no name"),
+			Entry("duplicate rule", `rules: [{name: a, action: allow, tools: ["*"]}, {name: a, action: deny, tools: ["*"]}]`, `rule "a" is defined more than once`),
+			Entry("unknown action", `rules: [{name: a, action: block, tools: ["*"]}]`, `rule "a" has invalid action "block"`),
+			Entry("no tools", `rules: [{name: a, action: allow}]`, `rule "a" lists no tools`),
+			Entry("bad pattern", `rules: [{name: a, action: allow, tools: ["*"], projects: ["team-["]}]`, `rule "a" has invalid pattern "team-["`),
+			Entry("unknown field", `rules: [{name: a, action: allow, tools: ["*"], project: ["x"]}]`, "failed to parse policy"),
+		)
+	})
+
+	Describe("Load", func() {
+		It("should read a policy file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.yaml")
+			Expect(os.WriteFile(path, []byte(examplePolicy), 0600)).To(Succeed())
+
+			p, err := Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.Rules).To(HaveLen(3))
+		})
+
+		It("should fail on a missing file", func() {
+			_, err := Load(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+			Expect(err).To(MatchError(ContainSubstring("failed to read policy file")))
+		})
+	})
+
+	Describe("Check", func() {
+		var p *Policy
+
+		BeforeEach(func() {
+			var err error
+			p, err = Parse([]byte(examplePolicy))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("decisions",
+			func(tool string, target Target, rule string) {
+				err := p.Check(tool, target)
+				if rule == "" {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+				var denied *DeniedError
+				Expect(errors.As(err, &denied)).To(BeTrue())
+				Expect(denied.Rule).To(Equal(rule))
+				Expect(denied.Tool).To(Equal(tool))
+				Expect(denied.Target).To(Equal(target))
+			},
+			Entry("unscoped allow", "argocd_list_applications", Target{}, ""),
+			Entry("scoped allow", "argocd_sync_application", Target{Project: "team-a", ClusterServer: "https://staging.example.com"}, ""),
+			Entry("deny by cluster server", "argocd_sync_application", Target{Project: "team-a", ClusterServer: "https://prod.example.com"}, "no-prod-syncs"),
+			Entry("deny by cluster name", "argocd_sync_application", Target{Project: "team-a", ClusterServer: "https://10.0.0.1", ClusterName: "eu-prod"}, "no-prod-syncs"),
+			Entry("default deny outside the projects", "argocd_sync_application", Target{Project: "platform", ClusterServer: "https://staging.example.com"}, DefaultRuleName),
+			Entry("default deny for other tools", "argocd_delete_application", Target{Project: "team-a"}, DefaultRuleName),
+			Entry("scoped rules don't match a missing target", "argocd_sync_application", Target{}, DefaultRuleName),
+		)
+
+		It("should allow unmatched calls when the default action is allow", func() {
+			p.DefaultAction = ActionAllow
+			Expect(p.Check("argocd_delete_application", Target{Project: "platform"})).To(Succeed())
+		})
+
+		It("should match namespaces", func() {
+			p, err := Parse([]byte(`rules: [{name: no-kube-system, action: deny, tools: ["*"], namespaces: ["kube-*"]}]
+defaultAction: allow`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.Check("argocd_sync_application", Target{Namespace: "kube-system"})).To(MatchError(ContainSubstring(`matched rule "no-kube-system"`)))
+			Expect(p.Check("argocd_sync_application", Target{Namespace: "web"})).To(Succeed())
+		})
+	})
+
+	Describe("NeedsTarget", func() {
+		It("should report whether any rule for the tool is scoped", func() {
+			p, err := Parse([]byte(examplePolicy))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.NeedsTarget("argocd_sync_application")).To(BeTrue())
+			Expect(p.NeedsTarget("argocd_get_application")).To(BeFalse())
+			Expect(p.NeedsTarget("argocd_delete_application")).To(BeFalse())
+		})
+
+		It("should stop at the first unscoped rule for the tool", func() {
+			p, err := Parse([]byte(`rules: [{name: a, action: deny, tools: ["argocd_sync_*"]}, {name: b, action: allow, tools: ["*"], projects: ["team-*"]}]`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.NeedsTarget("argocd_sync_application")).To(BeFalse())
+			Expect(p.NeedsTarget("argocd_get_application")).To(BeTrue())
+		})
+	})
+
+	Describe("DeniedError", func() {
+		It("should name the rule and the target", func() {
+			err := &DeniedError{Tool: "argocd_sync_application", Rule: "no-prod-syncs", Target: Target{Project: "team-a", ClusterServer: "https://prod", Namespace: "web"}}
+			Expect(err.Error()).To(Equal(`policy denied argocd_sync_application on project team-a, cluster https://prod, namespace web: matched rule "no-prod-syncs"`))
+		})
+
+		It("should explain a default denial", func() {
+			err := &DeniedError{Tool: "argocd_list_clusters", Rule: DefaultRuleName}
+			Expect(err.Error()).To(Equal("policy denied argocd_list_clusters on no target: no rule matched and the default action is deny"))
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package argo
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ReadOnlyAnnotations returns the annotations of a tool that only reads from Argo CD
+func ReadOnlyAnnotations() *mcp.ToolAnnotations {
+	destructive := false
+	return &mcp.ToolAnnotations{
+		ReadOnlyHint:    true,
+		DestructiveHint: &destructive,
+		IdempotentHint:  true,
+	}
+}
+
+// WriteAnnotations returns the annotations of a tool that changes Argo CD or the clusters it manages
+// destructive marks tools that can remove or overwrite state, idempotent those where repeating a call has no further effect
+func WriteAnnotations(destructive, idempotent bool) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		ReadOnlyHint:    false,
+		DestructiveHint: &destructive,
+		IdempotentHint:  idempotent,
+	}
+}
+
+// writeTools are the tools that change Argo CD or the clusters it manages directly
+var writeTools = map[string]bool{
+	"argocd_sync_application":     true,
+	"argocd_rollback_application": true,
+	"argocd_refresh_application":  true,
+	"argocd_terminate_operation":  true,
+	"argocd_run_resource_action":  true,
+	"argocd_delete_resource":      true,
+	"argocd_update_application":   true,
+	"argocd_create_application":   true,
+	"argocd_delete_application":   true,
+}
+
+// IsWriteTool reports whether a tool changes Argo CD or the clusters it manages
+func IsWriteTool(name string) bool {
+	return writeTools[name]
+}
@@ -0,0 +1,37 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Annotations", func() {
+	It("should mark read-only tools as safe to repeat", func() {
+		annotations := ReadOnlyAnnotations()
+		Expect(annotations.ReadOnlyHint).To(BeTrue())
+		Expect(*annotations.DestructiveHint).To(BeFalse())
+		Expect(annotations.IdempotentHint).To(BeTrue())
+	})
+
+	DescribeTable("WriteAnnotations",
+		func(destructive, idempotent bool) {
+			annotations := WriteAnnotations(destructive, idempotent)
+			Expect(annotations.ReadOnlyHint).To(BeFalse())
+			Expect(*annotations.DestructiveHint).To(Equal(destructive))
+			Expect(annotations.IdempotentHint).To(Equal(idempotent))
+		},
+		Entry("destructive", true, false),
+		Entry("additive and idempotent", false, true),
+	)
+
+	DescribeTable("IsWriteTool",
+		func(name string, expected bool) {
+			Expect(IsWriteTool(name)).To(Equal(expected))
+		},
+		Entry("sync", "argocd_sync_application", true),
+		Entry("delete", "argocd_delete_application", true),
+		Entry("read", "argocd_get_application", false),
+		Entry("wait", "argocd_wait_for_application", false),
+		Entry("unknown", "argocd_unknown", false),
+	)
+})
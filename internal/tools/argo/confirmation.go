@@ -0,0 +1,335 @@
+package argo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sethvargo/go-envconfig"
+)
+
+const (
+	// ConfirmationCodeTTL is how long a confirmation code stays valid
+	ConfirmationCodeTTL = 10 * time.Minute
+
+	// maxSummaryItems caps the resources listed in a confirmation prompt
+	maxSummaryItems = 20
+
+	// confirmationCodeAlphabet leaves out characters that are easy to misread
+	confirmationCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// ConfirmationConfig defines where confirmation codes are delivered for clients that can't ask the user
+type ConfirmationConfig struct {
+	File string `env:"MCP_CONFIRMATION_FILE"`
+}
+
+// NewConfirmationConfigFromEnv loads the confirmation configuration from environment variables
+func NewConfirmationConfigFromEnv(ctx context.Context) (*ConfirmationConfig, error) {
+	var cfg ConfirmationConfig
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to process environment variables: %w", err)
+	}
+	return &cfg, nil
+}
+
+// confirmationCodes holds the codes issued for operations awaiting the user's confirmation
+var confirmationCodes = newCodeStore()
+
+// EnableConfirmationCodes delivers confirmation codes to out, a channel only the user can read such as a file
+// Without it, destructive calls from clients that can't ask the user are refused
+func EnableConfirmationCodes(out io.Writer) {
+	confirmationCodes.mu.Lock()
+	defer confirmationCodes.mu.Unlock()
+	confirmationCodes.out = out
+}
+
+// confirmationSchema asks the user for a single yes/no answer
+var confirmationSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Confirm",
+			"description": "Carry out the operation described above",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// OperationSummary describes exactly what a destructive tool call is about to do
+type OperationSummary struct {
+	Operation     string   `json:"operation"`
+	Application   string   `json:"application"`
+	Project       string   `json:"project,omitempty"`
+	Cluster       string   `json:"cluster,omitempty"`
+	Namespace     string   `json:"namespace,omitempty"`
+	ResourceCount int      `json:"resourceCount"`
+	Resources     []string `json:"resources,omitempty"`
+	Prune         []string `json:"prune,omitempty"`
+	Details       []string `json:"details,omitempty"`
+}
+
+// newOperationSummary starts a summary of an operation on an application
+func newOperationSummary(operation string, app *v1alpha1.Application) OperationSummary {
+	return OperationSummary{
+		Operation:   operation,
+		Application: app.Name,
+		Project:     app.Spec.GetProject(),
+		Cluster:     destinationCluster(app.Spec.Destination),
+		Namespace:   app.Spec.Destination.Namespace,
+	}
+}
+
+// Message renders the summary as the prompt shown to the user
+func (s OperationSummary) Message() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Confirm: %s\n", s.Operation)
+	fmt.Fprintf(&b, "Application: %s\n", s.Application)
+	if s.Project != "" {
+		fmt.Fprintf(&b, "Project: %s\n", s.Project)
+	}
+	if s.Cluster != "" {
+		fmt.Fprintf(&b, "Cluster: %s\n", s.Cluster)
+	}
+	if s.Namespace != "" {
+		fmt.Fprintf(&b, "Namespace: %s\n", s.Namespace)
+	}
+	fmt.Fprintf(&b, "Resources affected: %d\n", s.ResourceCount)
+	writeSummaryList(&b, "Resources", s.Resources)
+	if len(s.Prune) > 0 {
+		writeSummaryList(&b, fmt.Sprintf("Pruned (deleted) resources: %d", len(s.Prune)), s.Prune)
+	}
+	for _, detail := range s.Details {
+		fmt.Fprintf(&b, "%s\n", detail)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeSummaryList writes a capped list of items under a heading
+func writeSummaryList(b *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", heading)
+	for i, item := range items {
+		if i == maxSummaryItems {
+			fmt.Fprintf(b, "  ... and %d more\n", len(items)-maxSummaryItems)
+			break
+		}
+		fmt.Fprintf(b, "  - %s\n", item)
+	}
+}
+
+// elicitFunc asks the user for input through the MCP client
+type elicitFunc func(ctx context.Context, params *mcp.ElicitParams) (*mcp.ElicitResult, error)
+
+// sessionElicitor returns the elicitation function of the request's session, or nil if the client doesn't support elicitation
+func sessionElicitor(req *mcp.CallToolRequest) elicitFunc {
+	if req == nil || req.Session == nil {
+		return nil
+	}
+	params := req.Session.InitializeParams()
+	if params == nil || params.Capabilities == nil || params.Capabilities.Elicitation == nil {
+		return nil
+	}
+	return req.Session.Elicit
+}
+
+// confirmationPendingError is returned while an operation waits for the user, so the same call can be made again once they confirm
+type confirmationPendingError struct {
+	message string
+}
+
+func (e *confirmationPendingError) Error() string {
+	return e.message
+}
+
+// pendingConfirmationKey holds the flag set when a call stops to wait for the user's confirmation
+type pendingConfirmationKey struct{}
+
+// trackPendingConfirmation returns a context that records whether a call made with it stopped to wait for the user's confirmation
+func trackPendingConfirmation(ctx context.Context) (context.Context, *atomic.Bool) {
+	pending := &atomic.Bool{}
+	return context.WithValue(ctx, pendingConfirmationKey{}, pending), pending
+}
+
+// notePendingConfirmation sets the tracking flag of ctx if err means the call waits for the user's confirmation
+func notePendingConfirmation(ctx context.Context, err error) {
+	var pending *confirmationPendingError
+	if !errors.As(err, &pending) {
+		return
+	}
+	if flag, ok := ctx.Value(pendingConfirmationKey{}).(*atomic.Bool); ok {
+		flag.Store(true)
+	}
+}
+
+// confirmOperation asks the user to confirm a destructive operation before it is carried out
+func confirmOperation(ctx context.Context, req *mcp.CallToolRequest, summary OperationSummary, code string) error {
+	err := requireConfirmation(ctx, sessionElicitor(req), confirmationCodes, summary, code, time.Now())
+	notePendingConfirmation(ctx, err)
+	return err
+}
+
+// requireConfirmation asks the user through elicitation if the client supports it
+// Otherwise the call is rejected unless it carries a code issued for exactly this operation
+// Codes only ever go to the store's output, never into a tool result, so the model can't confirm for the user
+func requireConfirmation(ctx context.Context, elicit elicitFunc, codes *codeStore, summary OperationSummary, code string, now time.Time) error {
+	if elicit != nil {
+		result, err := elicit(ctx, &mcp.ElicitParams{Message: summary.Message(), RequestedSchema: confirmationSchema})
+		if err != nil {
+			return fmt.Errorf("failed to ask the user to confirm the %s for application %q: %w", summary.Operation, summary.Application, err)
+		}
+		if result.Action != "accept" || result.Content["confirm"] != true {
+			return &confirmationPendingError{message: fmt.Sprintf("the user did not confirm the %s for application %q", summary.Operation, summary.Application)}
+		}
+		return nil
+	}
+
+	if !codes.enabled() {
+		return fmt.Errorf("the %s for application %q must be confirmed by the user, but this client can't ask the user directly and no confirmation file is configured (MCP_CONFIRMATION_FILE)", summary.Operation, summary.Application)
+	}
+
+	reason := "confirmation required, and this client can't ask the user directly"
+	if code != "" {
+		err := codes.redeem(summary, code, now)
+		if err == nil {
+			return nil
+		}
+		reason = fmt.Sprintf("%s; a new confirmation is required", err)
+	}
+	if err := codes.issue(summary, now); err != nil {
+		return err
+	}
+	return &confirmationPendingError{message: fmt.Sprintf("%s.\n\n%s\n\nShow this to the user. A one-time confirmation code for exactly this operation was written to the server's confirmation file, which only the user can read. Call again with confirm set to the code only if the user gives it to you", reason, summary.Message())}
+}
+
+// codeStore issues single-use confirmation codes and checks them against the operation they were issued for
+type codeStore struct {
+	mu    sync.Mutex
+	out   io.Writer
+	codes map[string]pendingConfirmation
+}
+
+// pendingConfirmation is an operation waiting for the user to pass on its code
+type pendingConfirmation struct {
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// newCodeStore creates a store that delivers no codes until it has an output
+func newCodeStore() *codeStore {
+	return &codeStore{codes: make(map[string]pendingConfirmation)}
+}
+
+// enabled reports whether codes can be delivered to the user
+func (c *codeStore) enabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.out != nil
+}
+
+// issue creates a code for the operation and writes it with the summary to the store's output
+func (c *codeStore) issue(summary OperationSummary, now time.Time) error {
+	code, err := newConfirmationCode()
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(ConfirmationCodeTTL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(now)
+	if _, err := fmt.Fprintf(c.out, "%s\nConfirmation code: %s (valid until %s)\n%s\n\n", formatGoTime(now), code, formatGoTime(expiresAt), summary.Message()); err != nil {
+		return fmt.Errorf("failed to deliver the confirmation code: %w", err)
+	}
+	c.codes[code] = pendingConfirmation{fingerprint: summaryFingerprint(summary), expiresAt: expiresAt}
+	return nil
+}
+
+// redeem uses up a code, which must have been issued for the same operation and not have expired
+// A code stops matching when the operation changes, e.g. when resources were added to the prune list since
+func (c *codeStore) redeem(summary OperationSummary, code string, now time.Time) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending, ok := c.codes[code]
+	delete(c.codes, code)
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown confirmation code")
+	case now.After(pending.expiresAt):
+		return fmt.Errorf("confirmation code has expired")
+	case pending.fingerprint != summaryFingerprint(summary):
+		return fmt.Errorf("confirmation code does not match this operation")
+	}
+	return nil
+}
+
+// expire removes codes past their expiry; the caller must hold the lock
+func (c *codeStore) expire(now time.Time) {
+	for code, pending := range c.codes {
+		if now.After(pending.expiresAt) {
+			delete(c.codes, code)
+		}
+	}
+}
+
+// summaryFingerprint hashes everything the user is shown about an operation
+func summaryFingerprint(summary OperationSummary) string {
+	data, _ := json.Marshal(summary)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newConfirmationCode generates a random code in the form XXXX-XXXX that is easy to read out and type
+func newConfirmationCode() (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation code: %w", err)
+	}
+	code := make([]byte, 0, 9)
+	for i, b := range random {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, confirmationCodeAlphabet[int(b)%len(confirmationCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// destinationCluster describes the cluster an application deploys to
+func destinationCluster(destination v1alpha1.ApplicationDestination) string {
+	switch {
+	case destination.Name != "" && destination.Server != "":
+		return fmt.Sprintf("%s (%s)", destination.Name, destination.Server)
+	case destination.Name != "":
+		return destination.Name
+	default:
+		return destination.Server
+	}
+}
+
+// resourceLabel formats a resource for a confirmation prompt
+func resourceLabel(group, kind, namespace, name string) string {
+	if group != "" {
+		kind = group + "/" + kind
+	}
+	if namespace != "" {
+		return fmt.Sprintf("%s %s/%s", kind, namespace, name)
+	}
+	return fmt.Sprintf("%s %s", kind, name)
+}
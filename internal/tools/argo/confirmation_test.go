@@ -0,0 +1,250 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Confirmation", func() {
+	var (
+		summary OperationSummary
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		app := &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "guestbook"},
+			Spec: v1alpha1.ApplicationSpec{
+				Project:     "team-a",
+				Destination: v1alpha1.ApplicationDestination{Name: "prod", Server: "https://prod.example.com", Namespace: "web"},
+			},
+		}
+		summary = newOperationSummary("sync with prune", app)
+		summary.ResourceCount = 12
+		summary.Prune = []string{"apps/Deployment web/old"}
+		now = time.Unix(1700000000, 0)
+	})
+
+	Describe("Message", func() {
+		It("should describe the application, cluster, resource count and prune list", func() {
+			Expect(summary.Message()).To(Equal(strings.Join([]string{
+				"Confirm: sync with prune",
+				"Application: guestbook",
+				"Project: team-a",
+				"Cluster: prod (https://prod.example.com)",
+				"Namespace: web",
+				"Resources affected: 12",
+				"Pruned (deleted) resources: 1:",
+				"  - apps/Deployment web/old",
+			}, "\n")))
+		})
+
+		It("should cap long resource lists", func() {
+			for i := 0; i < maxSummaryItems+5; i++ {
+				summary.Resources = append(summary.Resources, fmt.Sprintf("ConfigMap web/cm-%d", i))
+			}
+			Expect(summary.Message()).To(ContainSubstring("  ... and 5 more"))
+			Expect(summary.Message()).NotTo(ContainSubstring("cm-20"))
+		})
+	})
+
+	Describe("requireConfirmation with elicitation", func() {
+		elicitWith := func(result *mcp.ElicitResult, err error, asked *string) elicitFunc {
+			return func(_ context.Context, params *mcp.ElicitParams) (*mcp.ElicitResult, error) {
+				*asked = params.Message
+				return result, err
+			}
+		}
+
+		It("should proceed when the user accepts", func() {
+			var asked string
+			elicit := elicitWith(&mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirm": true}}, nil, &asked)
+			Expect(requireConfirmation(context.Background(), elicit, newCodeStore(), summary, "", now)).To(Succeed())
+			Expect(asked).To(Equal(summary.Message()))
+		})
+
+		DescribeTable("refusals",
+			func(result *mcp.ElicitResult) {
+				var asked string
+				err := requireConfirmation(context.Background(), elicitWith(result, nil, &asked), newCodeStore(), summary, "", now)
+				Expect(err).To(MatchError(`the user did not confirm the sync with prune for application "guestbook"`))
+			},
+			Entry("declined", &mcp.ElicitResult{Action: "decline"}),
+			Entry("cancelled", &mcp.ElicitResult{Action: "cancel"}),
+			Entry("accepted without confirming", &mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirm": false}}),
+		)
+
+		It("should ask even when a code is supplied", func() {
+			var asked string
+			err := requireConfirmation(context.Background(), elicitWith(&mcp.ElicitResult{Action: "decline"}, nil, &asked), newCodeStore(), summary, "ABCD-EFGH", now)
+			Expect(err).To(HaveOccurred())
+			Expect(asked).NotTo(BeEmpty())
+		})
+
+		It("should fail when the client can't be asked", func() {
+			var asked string
+			err := requireConfirmation(context.Background(), elicitWith(nil, fmt.Errorf("connection closed"), &asked), newCodeStore(), summary, "", now)
+			Expect(err).To(MatchError(ContainSubstring("failed to ask the user to confirm the sync with prune")))
+		})
+	})
+
+	Describe("requireConfirmation without elicitation", func() {
+		var (
+			codes *codeStore
+			out   *strings.Builder
+		)
+
+		BeforeEach(func() {
+			out = &strings.Builder{}
+			codes = newCodeStore()
+			codes.out = out
+		})
+
+		// issuedCode returns the last code written to the output
+		issuedCode := func() string {
+			lines := regexp.MustCompile(`Confirmation code: (\S+)`).FindAllStringSubmatch(out.String(), -1)
+			Expect(lines).NotTo(BeEmpty())
+			return lines[len(lines)-1][1]
+		}
+
+		It("should refuse outright when codes can't be delivered", func() {
+			err := requireConfirmation(context.Background(), nil, newCodeStore(), summary, "", now)
+			Expect(err).To(MatchError(ContainSubstring("no confirmation file is configured")))
+		})
+
+		It("should reject the call and deliver a code only out of band", func() {
+			err := requireConfirmation(context.Background(), nil, codes, summary, "", now)
+			Expect(err).To(MatchError(ContainSubstring("confirmation required")))
+			Expect(err.Error()).To(ContainSubstring(summary.Message()))
+
+			code := issuedCode()
+			Expect(code).To(MatchRegexp(`^[A-Z2-9]{4}-[A-Z2-9]{4}$`))
+			Expect(err.Error()).NotTo(ContainSubstring(code))
+			Expect(out.String()).To(ContainSubstring(summary.Message()))
+		})
+
+		It("should accept a code issued for the same operation once", func() {
+			Expect(requireConfirmation(context.Background(), nil, codes, summary, "", now)).NotTo(Succeed())
+			code := issuedCode()
+
+			Expect(requireConfirmation(context.Background(), nil, codes, summary, strings.ToLower(code), now.Add(time.Minute))).To(Succeed())
+			err := requireConfirmation(context.Background(), nil, codes, summary, code, now.Add(time.Minute))
+			Expect(err).To(MatchError(ContainSubstring("unknown confirmation code")))
+		})
+
+		It("should reject a code once the operation changed", func() {
+			Expect(requireConfirmation(context.Background(), nil, codes, summary, "", now)).NotTo(Succeed())
+			code := issuedCode()
+
+			summary.Prune = append(summary.Prune, "Service web/old")
+			err := requireConfirmation(context.Background(), nil, codes, summary, code, now)
+			Expect(err).To(MatchError(ContainSubstring("confirmation code does not match this operation")))
+			Expect(err.Error()).To(ContainSubstring("Service web/old"))
+			Expect(issuedCode()).NotTo(Equal(code))
+		})
+
+		It("should reject an expired code", func() {
+			Expect(requireConfirmation(context.Background(), nil, codes, summary, "", now)).NotTo(Succeed())
+			err := requireConfirmation(context.Background(), nil, codes, summary, issuedCode(), now.Add(ConfirmationCodeTTL+time.Second))
+			Expect(err).To(MatchError(ContainSubstring("confirmation code has expired")))
+		})
+
+		It("should reject codes that were never issued", func() {
+			err := requireConfirmation(context.Background(), nil, codes, summary, "yes", now)
+			Expect(err).To(MatchError(ContainSubstring("unknown confirmation code")))
+		})
+	})
+
+	Describe("confirmOperation", func() {
+		It("should only elicit from clients that support it", func() {
+			server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+			mcp.AddTool(server, &mcp.Tool{Name: "destroy"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, struct{}, error) {
+				return nil, struct{}{}, confirmOperation(ctx, req, summary, "")
+			})
+
+			call := func(opts *mcp.ClientOptions) *mcp.CallToolResult {
+				serverTransport, clientTransport := mcp.NewInMemoryTransports()
+				serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+				Expect(err).NotTo(HaveOccurred())
+				defer serverSession.Close()
+
+				client := mcp.NewClient(&mcp.Implementation{Name: "client"}, opts)
+				clientSession, err := client.Connect(context.Background(), clientTransport, nil)
+				Expect(err).NotTo(HaveOccurred())
+				defer clientSession.Close()
+
+				result, err := clientSession.CallTool(context.Background(), &mcp.CallToolParams{Name: "destroy"})
+				Expect(err).NotTo(HaveOccurred())
+				return result
+			}
+
+			var asked string
+			result := call(&mcp.ClientOptions{ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+				asked = req.Params.Message
+				return &mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirm": true}}, nil
+			}})
+			Expect(result.IsError).To(BeFalse())
+			Expect(asked).To(Equal(summary.Message()))
+
+			result = call(nil)
+			Expect(result.IsError).To(BeTrue())
+			Expect(result.Content[0].(*mcp.TextContent).Text).To(ContainSubstring("must be confirmed by the user"))
+		})
+
+		It("should not elicit without a session", func() {
+			Expect(sessionElicitor(nil)).To(BeNil())
+			Expect(sessionElicitor(&mcp.CallToolRequest{})).To(BeNil())
+		})
+	})
+
+	Describe("pending confirmations", func() {
+		It("should mark only calls that wait for the user as pending", func() {
+			declined := func(context.Context, *mcp.ElicitParams) (*mcp.ElicitResult, error) {
+				return &mcp.ElicitResult{Action: "decline"}, nil
+			}
+			codes := newCodeStore()
+			codes.out = &strings.Builder{}
+
+			for _, err := range []error{
+				requireConfirmation(context.Background(), declined, newCodeStore(), summary, "", now),
+				requireConfirmation(context.Background(), nil, codes, summary, "", now),
+			} {
+				ctx, pending := trackPendingConfirmation(context.Background())
+				notePendingConfirmation(ctx, err)
+				Expect(pending.Load()).To(BeTrue(), err.Error())
+			}
+
+			for _, err := range []error{
+				requireConfirmation(context.Background(), nil, newCodeStore(), summary, "", now),
+				fmt.Errorf("connection lost"),
+				nil,
+			} {
+				ctx, pending := trackPendingConfirmation(context.Background())
+				notePendingConfirmation(ctx, err)
+				Expect(pending.Load()).To(BeFalse())
+			}
+		})
+
+		It("should ignore contexts that don't track confirmations", func() {
+			notePendingConfirmation(context.Background(), &confirmationPendingError{message: "confirmation required"})
+		})
+	})
+
+	DescribeTable("resourceLabel",
+		func(group, kind, namespace, name, expected string) {
+			Expect(resourceLabel(group, kind, namespace, name)).To(Equal(expected))
+		},
+		Entry("namespaced", "apps", "Deployment", "web", "api", "apps/Deployment web/api"),
+		Entry("core", "", "Service", "web", "api", "Service web/api"),
+		Entry("cluster-scoped", "", "Namespace", "", "web", "Namespace web"),
+	)
+})
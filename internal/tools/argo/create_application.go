@@ -0,0 +1,303 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultProject is the project applications are created in when none is given
+const DefaultProject = "default"
+
+// CreateApplicationInput defines the input parameters for creating an application
+type CreateApplicationInput struct {
+	Name                 string                    `json:"name" jsonschema:"application name"`
+	AppNamespace         string                    `json:"appNamespace,omitempty" jsonschema:"optional namespace to create the Application resource in"`
+	Project              string                    `json:"project,omitempty" jsonschema:"project the application belongs to (default: default)"`
+	RepoURL              string                    `json:"repoURL,omitempty" jsonschema:"git or Helm repository URL of a single-source application"`
+	Path                 string                    `json:"path,omitempty" jsonschema:"directory in a git repository, for a single-source application"`
+	Chart                string                    `json:"chart,omitempty" jsonschema:"chart name in a Helm repository, for a single-source application"`
+	Revision             string                    `json:"revision,omitempty" jsonschema:"git revision or chart version, for a single-source application"`
+	Sources              []CreateApplicationSource `json:"sources,omitempty" jsonschema:"sources of a multi-source application, instead of repoURL, path, chart and revision"`
+	DestinationServer    string                    `json:"destinationServer,omitempty" jsonschema:"API server URL of the destination cluster; set this or destinationName"`
+	DestinationName      string                    `json:"destinationName,omitempty" jsonschema:"name of the destination cluster; set this or destinationServer"`
+	DestinationNamespace string                    `json:"destinationNamespace,omitempty" jsonschema:"namespace to deploy into"`
+	SyncPolicy           string                    `json:"syncPolicy,omitempty" jsonschema:"automated to enable automated sync or none (default) for manual sync"`
+	AutoPrune            bool                      `json:"autoPrune,omitempty" jsonschema:"let automated sync prune resources"`
+	SelfHeal             bool                      `json:"selfHeal,omitempty" jsonschema:"let automated sync revert changes made in the cluster"`
+	SyncOptions          []string                  `json:"syncOptions,omitempty" jsonschema:"sync options in Key=value form, e.g. CreateNamespace=true"`
+	Upsert               bool                      `json:"upsert,omitempty" jsonschema:"update the application if it already exists instead of failing"`
+	ValidateOnly         bool                      `json:"validateOnly,omitempty" jsonschema:"check the input and project permissions and return the manifest without creating the application"`
+}
+
+// CreateApplicationSource describes one source of a multi-source application
+type CreateApplicationSource struct {
+	RepoURL  string `json:"repoURL" jsonschema:"git or Helm repository URL"`
+	Path     string `json:"path,omitempty" jsonschema:"directory in a git repository"`
+	Chart    string `json:"chart,omitempty" jsonschema:"chart name in a Helm repository"`
+	Revision string `json:"revision,omitempty" jsonschema:"git revision or chart version"`
+	Ref      string `json:"ref,omitempty" jsonschema:"name other sources can use to reference this source's files, e.g. for Helm value files"`
+}
+
+// CreateApplicationOutput defines the output structure for creating an application
+type CreateApplicationOutput struct {
+	Name         string `json:"name"`
+	Project      string `json:"project"`
+	ValidateOnly bool   `json:"validateOnly"`
+	Created      bool   `json:"created" jsonschema:"true if the application was created or updated"`
+	Manifest     string `json:"manifest" jsonschema:"YAML manifest of the application"`
+}
+
+// NewCreateApplicationHandler creates a CreateApplication handler with the provided AppContext
+func NewCreateApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, CreateApplicationInput) (*mcp.CallToolResult, CreateApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input CreateApplicationInput) (*mcp.CallToolResult, CreateApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_create_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("create_application completed", "duration", duration)
+		}()
+
+		app, err := buildApplication(input)
+		if err != nil {
+			return nil, CreateApplicationOutput{}, err
+		}
+
+		proj, err := getProject(ctx, appCtx, app.Spec.Project)
+		if err != nil {
+			return nil, CreateApplicationOutput{}, err
+		}
+		clusters := func() ([]v1alpha1.Cluster, error) {
+			return loadClusters(ctx, appCtx)
+		}
+		if err := validateProjectPermits(proj, app, clusters); err != nil {
+			return nil, CreateApplicationOutput{}, fmt.Errorf("cannot create application %q: %w", app.Name, err)
+		}
+
+		manifest, err := renderApplicationManifest(app)
+		if err != nil {
+			return nil, CreateApplicationOutput{}, fmt.Errorf("failed to render application %q: %w", app.Name, err)
+		}
+		output := CreateApplicationOutput{
+			Name:         app.Name,
+			Project:      app.Spec.Project,
+			ValidateOnly: input.ValidateOnly,
+			Manifest:     manifest,
+		}
+		if input.ValidateOnly {
+			return nil, output, nil
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, CreateApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		l.Infow("Creating application", "name", app.Name, "project", app.Spec.Project, "upsert", input.Upsert)
+		validate := true
+		createRequest := &application.ApplicationCreateRequest{
+			Application: app,
+			Upsert:      &input.Upsert,
+			Validate:    &validate,
+		}
+		created, err := appClient.Create(ctx, createRequest)
+		if err != nil {
+			return nil, CreateApplicationOutput{}, fmt.Errorf("failed to create application %q: %w", app.Name, err)
+		}
+
+		appCtx.UpsertCachedApplication(created)
+		output.Created = true
+
+		return nil, output, nil
+	}
+}
+
+// buildApplication validates the input and converts it into an Application
+func buildApplication(input CreateApplicationInput) (*v1alpha1.Application, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+
+	project := input.Project
+	if project == "" {
+		project = DefaultProject
+	}
+	app := &v1alpha1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: input.Name, Namespace: input.AppNamespace},
+		Spec: v1alpha1.ApplicationSpec{
+			Project: project,
+			Destination: v1alpha1.ApplicationDestination{
+				Server:    input.DestinationServer,
+				Name:      input.DestinationName,
+				Namespace: input.DestinationNamespace,
+			},
+		},
+	}
+
+	singleSource := input.RepoURL != "" || input.Path != "" || input.Chart != "" || input.Revision != ""
+	switch {
+	case singleSource && len(input.Sources) > 0:
+		return nil, fmt.Errorf("set either repoURL, path, chart and revision or sources, not both")
+	case len(input.Sources) > 0:
+		for i, source := range input.Sources {
+			built, err := buildSource(source)
+			if err != nil {
+				return nil, fmt.Errorf("source %d: %w", i+1, err)
+			}
+			app.Spec.Sources = append(app.Spec.Sources, built)
+		}
+	default:
+		built, err := buildSource(CreateApplicationSource{RepoURL: input.RepoURL, Path: input.Path, Chart: input.Chart, Revision: input.Revision})
+		if err != nil {
+			return nil, err
+		}
+		app.Spec.Source = &built
+	}
+
+	if (input.DestinationServer == "") == (input.DestinationName == "") {
+		return nil, fmt.Errorf("exactly one of destinationServer and destinationName is required")
+	}
+
+	switch input.SyncPolicy {
+	case "", SyncPolicyNone:
+		if input.AutoPrune || input.SelfHeal {
+			return nil, fmt.Errorf("autoPrune and selfHeal require an automated sync policy")
+		}
+	case SyncPolicyAutomated:
+		app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{
+			Automated: &v1alpha1.SyncPolicyAutomated{Prune: input.AutoPrune, SelfHeal: input.SelfHeal},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sync policy %q, expected %q or %q", input.SyncPolicy, SyncPolicyAutomated, SyncPolicyNone)
+	}
+
+	if len(input.SyncOptions) > 0 {
+		for _, option := range input.SyncOptions {
+			if !strings.Contains(option, "=") {
+				return nil, fmt.Errorf("invalid sync option %q, expected Key=value", option)
+			}
+		}
+		if app.Spec.SyncPolicy == nil {
+			app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{}
+		}
+		app.Spec.SyncPolicy.SyncOptions = input.SyncOptions
+	}
+
+	return app, nil
+}
+
+// buildSource validates a source and converts it into an ApplicationSource
+func buildSource(source CreateApplicationSource) (v1alpha1.ApplicationSource, error) {
+	if source.RepoURL == "" {
+		return v1alpha1.ApplicationSource{}, fmt.Errorf("repoURL is required")
+	}
+	if source.Path != "" && source.Chart != "" {
+		return v1alpha1.ApplicationSource{}, fmt.Errorf("set either path or chart, not both")
+	}
+	if source.Path == "" && source.Chart == "" && source.Ref == "" {
+		return v1alpha1.ApplicationSource{}, fmt.Errorf("path or chart is required")
+	}
+	return v1alpha1.ApplicationSource{
+		RepoURL:        source.RepoURL,
+		Path:           source.Path,
+		Chart:          source.Chart,
+		TargetRevision: source.Revision,
+		Ref:            source.Ref,
+	}, nil
+}
+
+// validateProjectPermits checks that an application's project allows its sources and destination
+// Argo CD checks this as well, but doing it first gives a clearer error that names the project's allowed values
+func validateProjectPermits(proj *v1alpha1.AppProject, app *v1alpha1.Application, clusters func() ([]v1alpha1.Cluster, error)) error {
+	for _, source := range app.Spec.GetSources() {
+		if !proj.IsSourcePermitted(source) {
+			return fmt.Errorf("project %q does not allow source repository %q, allowed: %v", proj.Name, source.RepoURL, proj.Spec.SourceRepos)
+		}
+	}
+
+	// Project destinations may be listed by server or by name, so resolve whichever one is missing
+	dest := app.Spec.Destination
+	if dest.Server == "" || dest.Name == "" {
+		items, err := clusters()
+		if err != nil {
+			return err
+		}
+		for _, cluster := range items {
+			if (dest.Name != "" && cluster.Name == dest.Name) || (dest.Server != "" && cluster.Server == dest.Server) {
+				dest.Name, dest.Server = cluster.Name, cluster.Server
+				break
+			}
+		}
+	}
+
+	projectClusters := func(project string) ([]*v1alpha1.Cluster, error) {
+		items, err := clusters()
+		if err != nil {
+			return nil, err
+		}
+		var result []*v1alpha1.Cluster
+		for i := range items {
+			if items[i].Project == project {
+				result = append(result, &items[i])
+			}
+		}
+		return result, nil
+	}
+	permitted, err := proj.IsDestinationPermitted(dest, projectClusters)
+	if err != nil {
+		return err
+	}
+	if !permitted {
+		allowed := make([]string, 0, len(proj.Spec.Destinations))
+		for _, d := range proj.Spec.Destinations {
+			allowed = append(allowed, fmt.Sprintf("%s%s/%s", d.Server, d.Name, d.Namespace))
+		}
+		return fmt.Errorf("project %q does not allow destination %s%s/%s, allowed: %v", proj.Name, app.Spec.Destination.Server, app.Spec.Destination.Name, dest.Namespace, allowed)
+	}
+	return nil
+}
+
+// renderApplicationManifest renders the YAML manifest a user would write for an application
+// Status and server-populated metadata are left out
+func renderApplicationManifest(app *v1alpha1.Application) (string, error) {
+	metadata := map[string]interface{}{"name": app.Name}
+	if app.Namespace != "" {
+		metadata["namespace"] = app.Namespace
+	}
+	data, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": app.APIVersion,
+		"kind":       app.Kind,
+		"metadata":   metadata,
+		"spec":       app.Spec,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// loadClusters returns the known clusters, refreshing the cluster cache if it has expired
+func loadClusters(ctx context.Context, appCtx *appcontext.AppContext) ([]v1alpha1.Cluster, error) {
+	if cached := appCtx.GetCachedClusters(); cached != nil {
+		return cached.Items, nil
+	}
+	if err := appCtx.RefreshClusterCache(ctx); err != nil {
+		return nil, err
+	}
+	if cached := appCtx.GetCachedClusters(); cached != nil {
+		return cached.Items, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,161 @@
+package argo
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Create Application", func() {
+	validInput := func() CreateApplicationInput {
+		return CreateApplicationInput{
+			Name:                 "guestbook",
+			RepoURL:              "https://github.com/argoproj/argocd-example-apps",
+			Path:                 "guestbook",
+			Revision:             "HEAD",
+			DestinationServer:    "https://kubernetes.default.svc",
+			DestinationNamespace: "guestbook",
+		}
+	}
+
+	Describe("buildApplication", func() {
+		It("should build a single-source application in the default project", func() {
+			app, err := buildApplication(validInput())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.Name).To(Equal("guestbook"))
+			Expect(app.Spec.Project).To(Equal(DefaultProject))
+			Expect(app.Spec.Source).To(Equal(&v1alpha1.ApplicationSource{
+				RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+				Path:           "guestbook",
+				TargetRevision: "HEAD",
+			}))
+			Expect(app.Spec.Destination.Namespace).To(Equal("guestbook"))
+			Expect(app.Spec.SyncPolicy).To(BeNil())
+		})
+
+		It("should build a multi-source application", func() {
+			input := validInput()
+			input.RepoURL, input.Path, input.Revision = "", "", ""
+			input.Sources = []CreateApplicationSource{
+				{RepoURL: "https://charts.example.com", Chart: "redis", Revision: "17.0.0"},
+				{RepoURL: "https://github.com/example/values", Ref: "values"},
+			}
+
+			app, err := buildApplication(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.Spec.Source).To(BeNil())
+			Expect(app.Spec.Sources).To(HaveLen(2))
+			Expect(app.Spec.Sources[1].Ref).To(Equal("values"))
+		})
+
+		It("should set an automated sync policy with sync options", func() {
+			input := validInput()
+			input.SyncPolicy = SyncPolicyAutomated
+			input.AutoPrune = true
+			input.SyncOptions = []string{"CreateNamespace=true"}
+
+			app, err := buildApplication(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.Spec.SyncPolicy.Automated).To(Equal(&v1alpha1.SyncPolicyAutomated{Prune: true}))
+			Expect(app.Spec.SyncPolicy.SyncOptions).To(Equal(v1alpha1.SyncOptions{"CreateNamespace=true"}))
+		})
+
+		DescribeTable("invalid input",
+			func(modify func(*CreateApplicationInput), expectedError string) {
+				input := validInput()
+				modify(&input)
+				_, err := buildApplication(input)
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			},
+			Entry("missing name", func(i *CreateApplicationInput) { i.Name = "" }, "application name is required"),
+			Entry("missing repo", func(i *CreateApplicationInput) { i.RepoURL = "" }, "repoURL is required"),
+			Entry("path and chart", func(i *CreateApplicationInput) { i.Chart = "redis" }, "set either path or chart"),
+			Entry("single and multi source", func(i *CreateApplicationInput) {
+				i.Sources = []CreateApplicationSource{{RepoURL: "https://charts.example.com", Chart: "redis"}}
+			}, "not both"),
+			Entry("invalid source in list", func(i *CreateApplicationInput) {
+				i.RepoURL, i.Path, i.Revision = "", "", ""
+				i.Sources = []CreateApplicationSource{{RepoURL: "https://charts.example.com"}}
+			}, "source 1: path or chart is required"),
+			Entry("no destination", func(i *CreateApplicationInput) { i.DestinationServer = "" }, "exactly one of destinationServer and destinationName"),
+			Entry("two destinations", func(i *CreateApplicationInput) { i.DestinationName = "in-cluster" }, "exactly one of destinationServer and destinationName"),
+			Entry("prune without automation", func(i *CreateApplicationInput) { i.AutoPrune = true }, "require an automated sync policy"),
+			Entry("unknown sync policy", func(i *CreateApplicationInput) { i.SyncPolicy = "sometimes" }, `unsupported sync policy "sometimes"`),
+			Entry("malformed sync option", func(i *CreateApplicationInput) { i.SyncOptions = []string{"CreateNamespace"} }, `invalid sync option "CreateNamespace"`),
+		)
+	})
+
+	Describe("validateProjectPermits", func() {
+		var proj *v1alpha1.AppProject
+		clusters := func() ([]v1alpha1.Cluster, error) {
+			return []v1alpha1.Cluster{{Name: "in-cluster", Server: "https://kubernetes.default.svc"}}, nil
+		}
+
+		BeforeEach(func() {
+			proj = &v1alpha1.AppProject{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+				Spec: v1alpha1.AppProjectSpec{
+					SourceRepos:  []string{"https://github.com/argoproj/*"},
+					Destinations: []v1alpha1.ApplicationDestination{{Server: "https://kubernetes.default.svc", Namespace: "guestbook*"}},
+				},
+			}
+		})
+
+		It("should allow a permitted source and destination", func() {
+			app, err := buildApplication(validInput())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validateProjectPermits(proj, app, clusters)).To(Succeed())
+		})
+
+		It("should resolve a destination given by name", func() {
+			input := validInput()
+			input.DestinationServer, input.DestinationName = "", "in-cluster"
+			app, err := buildApplication(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validateProjectPermits(proj, app, clusters)).To(Succeed())
+		})
+
+		It("should reject a source outside the project", func() {
+			input := validInput()
+			input.RepoURL = "https://github.com/example/other"
+			app, err := buildApplication(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validateProjectPermits(proj, app, clusters)).To(MatchError(ContainSubstring(`project "team-a" does not allow source repository`)))
+		})
+
+		It("should reject a destination outside the project", func() {
+			input := validInput()
+			input.DestinationNamespace = "kube-system"
+			app, err := buildApplication(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validateProjectPermits(proj, app, clusters)).To(MatchError(ContainSubstring(`project "team-a" does not allow destination`)))
+		})
+
+		It("should surface cluster lookup failures", func() {
+			app, err := buildApplication(validInput())
+			Expect(err).NotTo(HaveOccurred())
+			failing := func() ([]v1alpha1.Cluster, error) { return nil, errors.New("permission denied") }
+			Expect(validateProjectPermits(proj, app, failing)).To(MatchError("permission denied"))
+		})
+	})
+
+	Describe("renderApplicationManifest", func() {
+		It("should render only what a user would write", func() {
+			app, err := buildApplication(validInput())
+			Expect(err).NotTo(HaveOccurred())
+
+			manifest, err := renderApplicationManifest(app)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(ContainSubstring("apiVersion: argoproj.io/v1alpha1"))
+			Expect(manifest).To(ContainSubstring("kind: Application"))
+			Expect(manifest).To(ContainSubstring("name: guestbook"))
+			Expect(manifest).To(ContainSubstring("path: guestbook"))
+			Expect(manifest).NotTo(ContainSubstring("status"))
+			Expect(manifest).NotTo(ContainSubstring("creationTimestamp"))
+		})
+	})
+})
@@ -0,0 +1,198 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// PropagationPolicyForeground deletes the application after its resources are gone, the Argo CD default
+	PropagationPolicyForeground = "foreground"
+
+	// PropagationPolicyBackground deletes the application first and its resources afterwards
+	PropagationPolicyBackground = "background"
+)
+
+// DeleteApplicationInput defines the input parameters for deleting an application
+type DeleteApplicationInput struct {
+	Name              string `json:"name" jsonschema:"application name"`
+	AppNamespace      string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Cascade           *bool  `json:"cascade,omitempty" jsonschema:"delete the application's resources along with it (default true); false leaves them running"`
+	PropagationPolicy string `json:"propagationPolicy,omitempty" jsonschema:"foreground (default) or background deletion of cascaded resources"`
+	Force             bool   `json:"force,omitempty" jsonschema:"allow cascading deletion of an app-of-apps parent, which also deletes its child applications"`
+	DryRun            bool   `json:"dryRun,omitempty" jsonschema:"list what would be removed without deleting anything"`
+	Confirm           string `json:"confirm,omitempty" jsonschema:"one-time confirmation code for clients that can't ask the user directly; only pass a code the user gave you"`
+}
+
+// DeleteApplicationOutput defines the output structure for deleting an application
+type DeleteApplicationOutput struct {
+	Name              string            `json:"name"`
+	Cascade           bool              `json:"cascade"`
+	PropagationPolicy string            `json:"propagationPolicy,omitempty"`
+	DryRun            bool              `json:"dryRun"`
+	Deleted           bool              `json:"deleted" jsonschema:"true if deletion was requested; Argo CD finishes it asynchronously"`
+	Resources         []ResourceRefInfo `json:"resources" jsonschema:"managed resources; removed with the application when cascading, left in place otherwise"`
+	Dependents        int               `json:"dependents" jsonschema:"number of resources owned by the managed resources, e.g. ReplicaSets and Pods"`
+	ChildApplications []string          `json:"childApplications,omitempty" jsonschema:"Applications managed by this one, if it is an app-of-apps parent"`
+}
+
+// ResourceRefInfo identifies a Kubernetes resource
+type ResourceRefInfo struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// NewDeleteApplicationHandler creates a DeleteApplication handler with the provided AppContext
+func NewDeleteApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, DeleteApplicationInput) (*mcp.CallToolResult, DeleteApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input DeleteApplicationInput) (*mcp.CallToolResult, DeleteApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_delete_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("delete_application completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, DeleteApplicationOutput{}, fmt.Errorf("application name is required")
+		}
+		cascade := input.Cascade == nil || *input.Cascade
+		policy, err := propagationPolicy(input.PropagationPolicy, cascade)
+		if err != nil {
+			return nil, DeleteApplicationOutput{}, err
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, DeleteApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		// Read the live application, so the cache is updated with the namespace it really lives in
+		query := &application.ApplicationQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+		app, err := appClient.Get(ctx, query)
+		if err != nil {
+			return nil, DeleteApplicationOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+		}
+
+		treeQuery := &application.ResourcesQuery{ApplicationName: &app.Name, AppNamespace: &app.Namespace}
+		tree, err := appClient.ResourceTree(ctx, treeQuery)
+		if err != nil {
+			return nil, DeleteApplicationOutput{}, fmt.Errorf("failed to get resource tree for application %q: %w", input.Name, err)
+		}
+
+		resources, dependents, children := planApplicationDeletion(tree.Nodes)
+		output := DeleteApplicationOutput{
+			Name:              input.Name,
+			Cascade:           cascade,
+			PropagationPolicy: policy,
+			DryRun:            input.DryRun,
+			Resources:         resources,
+			Dependents:        dependents,
+			ChildApplications: children,
+		}
+		if cascade && len(children) > 0 && !input.Force {
+			return nil, DeleteApplicationOutput{}, fmt.Errorf("application %q is the parent of %d applications %v, which would be deleted too; set force to confirm or cascade to false to keep them", input.Name, len(children), children)
+		}
+		if input.DryRun {
+			return nil, output, nil
+		}
+
+		summary := summarizeApplicationDeletion(app, cascade, policy, resources, dependents, children)
+		if err := confirmOperation(ctx, req, summary, input.Confirm); err != nil {
+			return nil, DeleteApplicationOutput{}, err
+		}
+
+		l.Infow("Deleting application", "name", input.Name, "cascade", cascade, "propagation_policy", policy, "resources", len(resources))
+		deleteRequest := &application.ApplicationDeleteRequest{
+			Name:         &app.Name,
+			AppNamespace: &app.Namespace,
+			Cascade:      &cascade,
+		}
+		if policy != "" {
+			deleteRequest.PropagationPolicy = &policy
+		}
+		if _, err := appClient.Delete(ctx, deleteRequest); err != nil {
+			return nil, DeleteApplicationOutput{}, fmt.Errorf("failed to delete application %q: %w", input.Name, err)
+		}
+
+		appCtx.RemoveCachedApplication(app.Name, app.Namespace)
+		output.Deleted = true
+
+		return nil, output, nil
+	}
+}
+
+// propagationPolicy validates the propagation policy, which only applies to cascading deletes
+func propagationPolicy(policy string, cascade bool) (string, error) {
+	switch policy {
+	case "":
+		if cascade {
+			return PropagationPolicyForeground, nil
+		}
+		return "", nil
+	case PropagationPolicyForeground, PropagationPolicyBackground:
+		if !cascade {
+			return "", fmt.Errorf("propagationPolicy only applies when cascade is enabled")
+		}
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported propagation policy %q, expected %q or %q", policy, PropagationPolicyForeground, PropagationPolicyBackground)
+	}
+}
+
+// planApplicationDeletion lists the resources an application manages directly and counts their dependents
+// Managed resources are the nodes without parents; Applications among them are child apps of an app-of-apps
+func planApplicationDeletion(nodes []v1alpha1.ResourceNode) ([]ResourceRefInfo, int, []string) {
+	resources := make([]ResourceRefInfo, 0)
+	dependents := 0
+	var children []string
+	for _, node := range nodes {
+		if len(node.ParentRefs) > 0 {
+			dependents++
+			continue
+		}
+		resources = append(resources, ResourceRefInfo{
+			Group:     node.Group,
+			Kind:      node.Kind,
+			Namespace: node.Namespace,
+			Name:      node.Name,
+		})
+		if node.Group == "argoproj.io" && node.Kind == "Application" {
+			children = append(children, node.Name)
+		}
+	}
+	return resources, dependents, children
+}
+
+// summarizeApplicationDeletion describes an application deletion for confirmation
+func summarizeApplicationDeletion(app *v1alpha1.Application, cascade bool, policy string, resources []ResourceRefInfo, dependents int, children []string) OperationSummary {
+	summary := newOperationSummary("application deletion", app)
+	if !cascade {
+		summary.Details = append(summary.Details, fmt.Sprintf("Cascade disabled: the application is removed and its %d resources are left running", len(resources)))
+		return summary
+	}
+
+	summary.ResourceCount = len(resources)
+	for _, resource := range resources {
+		summary.Resources = append(summary.Resources, resourceLabel(resource.Group, resource.Kind, resource.Namespace, resource.Name))
+	}
+	summary.Details = append(summary.Details, fmt.Sprintf("Cascade: %s deletion of the resources and their %d dependents", policy, dependents))
+	if len(children) > 0 {
+		summary.Details = append(summary.Details, fmt.Sprintf("Child applications deleted with it: %s", strings.Join(children, ", ")))
+	}
+	return summary
+}
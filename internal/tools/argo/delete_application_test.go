@@ -0,0 +1,99 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("Delete Application", func() {
+	DescribeTable("propagationPolicy",
+		func(policy string, cascade bool, expected, expectedError string) {
+			result, err := propagationPolicy(policy, cascade)
+			if expectedError != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("default when cascading", "", true, PropagationPolicyForeground, ""),
+		Entry("none without cascade", "", false, "", ""),
+		Entry("background", PropagationPolicyBackground, true, PropagationPolicyBackground, ""),
+		Entry("policy without cascade", PropagationPolicyBackground, false, "", "only applies when cascade is enabled"),
+		Entry("unknown policy", "orphan", true, "", `unsupported propagation policy "orphan"`),
+	)
+
+	Describe("planApplicationDeletion", func() {
+		It("should list managed resources and count dependents", func() {
+			deployment := v1alpha1.ResourceRef{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "web"}
+			nodes := []v1alpha1.ResourceNode{
+				{ResourceRef: deployment},
+				{ResourceRef: v1alpha1.ResourceRef{Kind: "Service", Namespace: "default", Name: "web"}},
+				{ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "ReplicaSet", Namespace: "default", Name: "web-abc"}, ParentRefs: []v1alpha1.ResourceRef{deployment}},
+				{ResourceRef: v1alpha1.ResourceRef{Kind: "Pod", Namespace: "default", Name: "web-abc-1"}, ParentRefs: []v1alpha1.ResourceRef{{Kind: "ReplicaSet", Name: "web-abc"}}},
+			}
+
+			resources, dependents, children := planApplicationDeletion(nodes)
+			Expect(resources).To(Equal([]ResourceRefInfo{
+				{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "web"},
+				{Kind: "Service", Namespace: "default", Name: "web"},
+			}))
+			Expect(dependents).To(Equal(2))
+			Expect(children).To(BeEmpty())
+		})
+
+		It("should detect child applications", func() {
+			nodes := []v1alpha1.ResourceNode{
+				{ResourceRef: v1alpha1.ResourceRef{Group: "argoproj.io", Kind: "Application", Namespace: "argocd", Name: "billing"}},
+				{ResourceRef: v1alpha1.ResourceRef{Group: "argoproj.io", Kind: "Application", Namespace: "argocd", Name: "guestbook"}},
+				{ResourceRef: v1alpha1.ResourceRef{Group: "argoproj.io", Kind: "AppProject", Namespace: "argocd", Name: "team-a"}},
+			}
+
+			resources, _, children := planApplicationDeletion(nodes)
+			Expect(resources).To(HaveLen(3))
+			Expect(children).To(Equal([]string{"billing", "guestbook"}))
+		})
+
+		It("should return an empty list for an application without resources", func() {
+			resources, dependents, children := planApplicationDeletion(nil)
+			Expect(resources).NotTo(BeNil())
+			Expect(resources).To(BeEmpty())
+			Expect(dependents).To(BeZero())
+			Expect(children).To(BeNil())
+		})
+	})
+
+	Describe("summarizeApplicationDeletion", func() {
+		var (
+			app       *v1alpha1.Application
+			resources []ResourceRefInfo
+		)
+
+		BeforeEach(func() {
+			app = &v1alpha1.Application{}
+			app.Name = "guestbook"
+			app.Spec.Destination = v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "default"}
+			resources = []ResourceRefInfo{{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "web"}}
+		})
+
+		It("should list the resources deleted with the application", func() {
+			summary := summarizeApplicationDeletion(app, true, PropagationPolicyForeground, resources, 3, []string{"billing"})
+			Expect(summary.Cluster).To(Equal("https://kubernetes.default.svc"))
+			Expect(summary.ResourceCount).To(Equal(1))
+			Expect(summary.Resources).To(Equal([]string{"apps/Deployment default/web"}))
+			Expect(summary.Details).To(Equal([]string{
+				"Cascade: foreground deletion of the resources and their 3 dependents",
+				"Child applications deleted with it: billing",
+			}))
+		})
+
+		It("should report no affected resources without cascade", func() {
+			summary := summarizeApplicationDeletion(app, false, "", resources, 3, nil)
+			Expect(summary.ResourceCount).To(BeZero())
+			Expect(summary.Resources).To(BeEmpty())
+			Expect(summary.Details).To(ConsistOf(ContainSubstring("its 1 resources are left running")))
+		})
+	})
+})
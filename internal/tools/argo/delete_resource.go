@@ -0,0 +1,169 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DeleteResourceInput defines the input parameters for deleting a managed resource
+type DeleteResourceInput struct {
+	Name               string `json:"name" jsonschema:"application name"`
+	AppNamespace       string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Group              string `json:"group,omitempty" jsonschema:"API group of the resource (empty for core resources)"`
+	Kind               string `json:"kind" jsonschema:"kind of the resource"`
+	Namespace          string `json:"namespace,omitempty" jsonschema:"namespace of the resource, matched exactly (empty only for cluster-scoped resources)"`
+	ResourceName       string `json:"resourceName" jsonschema:"name of the resource"`
+	Orphan             bool   `json:"orphan,omitempty" jsonschema:"leave the resource's dependents, e.g. a Deployment's ReplicaSets, in place"`
+	Force              bool   `json:"force,omitempty" jsonschema:"delete immediately without waiting for graceful termination, e.g. for stuck pods"`
+	AllowClusterScoped bool   `json:"allowClusterScoped,omitempty" jsonschema:"allow deleting cluster-scoped resources, including Namespaces, which are refused by default"`
+	Confirm            string `json:"confirm,omitempty" jsonschema:"one-time confirmation code for clients that can't ask the user directly; only pass a code the user gave you"`
+}
+
+// DeleteResourceOutput defines the output structure for deleting a managed resource
+type DeleteResourceOutput struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Orphan    bool   `json:"orphan"`
+	Force     bool   `json:"force"`
+}
+
+// NewDeleteResourceHandler creates a DeleteResource handler with the provided AppContext
+func NewDeleteResourceHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, DeleteResourceInput) (*mcp.CallToolResult, DeleteResourceOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input DeleteResourceInput) (*mcp.CallToolResult, DeleteResourceOutput, error) {
+		l := log.Logger().With("component", "argocd_delete_resource")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("delete_resource completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("application name is required")
+		}
+		if input.Kind == "" || input.ResourceName == "" {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("kind and resourceName are required")
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		// Always resolve through the resource tree, so the scope check relies on what is live rather than on the input
+		treeQuery := &application.ResourcesQuery{ApplicationName: &input.Name}
+		if input.AppNamespace != "" {
+			treeQuery.AppNamespace = &input.AppNamespace
+		}
+		tree, err := appClient.ResourceTree(ctx, treeQuery)
+		if err != nil {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("failed to get resource tree for application %q: %w", input.Name, err)
+		}
+		node, err := findDeletionNode(append(tree.Nodes, tree.OrphanedNodes...), input.Group, input.Kind, input.Namespace, input.ResourceName)
+		if err != nil {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("%w in application %q", err, input.Name)
+		}
+		query, err := resolveResourceRequest(ctx, appClient, input.Name, input.AppNamespace, node.Group, node.Version, node.Kind, node.Namespace, node.Name)
+		if err != nil {
+			return nil, DeleteResourceOutput{}, err
+		}
+		if err := validateResourceDeletion(query.GetKind(), query.GetNamespace(), input.AllowClusterScoped); err != nil {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("refusing to delete %q: %w", input.ResourceName, err)
+		}
+
+		app, err := appClient.Get(ctx, &application.ApplicationQuery{Name: query.Name, AppNamespace: query.AppNamespace})
+		if err != nil {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+		}
+		summary := newOperationSummary("resource deletion", app)
+		summary.ResourceCount = 1
+		summary.Resources = []string{resourceLabel(query.GetGroup(), query.GetKind(), query.GetNamespace(), query.GetResourceName())}
+		summary.Details = append(summary.Details, fmt.Sprintf("Orphan dependents: %t, force: %t", input.Orphan, input.Force))
+		if err := confirmOperation(ctx, req, summary, input.Confirm); err != nil {
+			return nil, DeleteResourceOutput{}, err
+		}
+
+		l.Infow("Deleting resource", "name", input.Name, "kind", query.GetKind(), "namespace", query.GetNamespace(), "resource", input.ResourceName, "orphan", input.Orphan, "force", input.Force)
+		deleteRequest := &application.ApplicationResourceDeleteRequest{
+			Name:         query.Name,
+			Namespace:    query.Namespace,
+			ResourceName: query.ResourceName,
+			Version:      query.Version,
+			Group:        query.Group,
+			Kind:         query.Kind,
+			Force:        &input.Force,
+			Orphan:       &input.Orphan,
+			AppNamespace: query.AppNamespace,
+		}
+		if _, err := appClient.DeleteResource(ctx, deleteRequest); err != nil {
+			return nil, DeleteResourceOutput{}, fmt.Errorf("failed to delete %s %q in application %q: %w", query.GetKind(), input.ResourceName, input.Name, err)
+		}
+
+		return nil, DeleteResourceOutput{
+			Group:     input.Group,
+			Version:   query.GetVersion(),
+			Kind:      query.GetKind(),
+			Namespace: query.GetNamespace(),
+			Name:      input.ResourceName,
+			Orphan:    input.Orphan,
+			Force:     input.Force,
+		}, nil
+	}
+}
+
+// validateResourceDeletion refuses to delete cluster-scoped resources unless explicitly allowed
+// Namespaces are called out separately, as deleting one takes everything in it along
+func validateResourceDeletion(kind, namespace string, allowClusterScoped bool) error {
+	if allowClusterScoped {
+		return nil
+	}
+	if kind == "Namespace" {
+		return fmt.Errorf("deleting a Namespace removes everything in it; set allowClusterScoped to confirm")
+	}
+	if namespace == "" {
+		return fmt.Errorf("%s is cluster-scoped; set allowClusterScoped to confirm", kind)
+	}
+	return nil
+}
+
+// findDeletionNode finds the resource to delete in the resource tree, matching its namespace exactly
+// Unlike the read tools, an empty namespace only matches cluster-scoped resources, so a deletion never picks one of several namespaces
+// If the resource only exists in other namespaces, the error lists them
+func findDeletionNode(nodes []v1alpha1.ResourceNode, group, kind, namespace, name string) (*v1alpha1.ResourceNode, error) {
+	var elsewhere []string
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Group != group || !strings.EqualFold(node.Kind, kind) || node.Name != name {
+			continue
+		}
+		if node.Namespace == namespace {
+			return node, nil
+		}
+		if !slices.Contains(elsewhere, node.Namespace) {
+			elsewhere = append(elsewhere, node.Namespace)
+		}
+	}
+
+	label := resourceLabel(group, kind, namespace, name)
+	switch {
+	case len(elsewhere) == 0:
+		return nil, fmt.Errorf("resource %s not found", label)
+	case namespace == "":
+		return nil, fmt.Errorf("resource %s is not cluster-scoped; set namespace to choose from: %s", label, strings.Join(elsewhere, ", "))
+	default:
+		return nil, fmt.Errorf("resource %s not found; it exists in namespaces: %s", label, strings.Join(elsewhere, ", "))
+	}
+}
@@ -0,0 +1,58 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("Delete Resource", func() {
+	DescribeTable("validateResourceDeletion",
+		func(kind, namespace string, allowClusterScoped bool, expectedError string) {
+			err := validateResourceDeletion(kind, namespace, allowClusterScoped)
+			if expectedError == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("namespaced pod", "Pod", "default", false, ""),
+		Entry("namespaced job", "Job", "batch", false, ""),
+		Entry("namespace", "Namespace", "", false, "deleting a Namespace removes everything in it"),
+		Entry("cluster-scoped kind", "ClusterRole", "", false, "ClusterRole is cluster-scoped"),
+		Entry("namespace when allowed", "Namespace", "", true, ""),
+		Entry("cluster-scoped kind when allowed", "ClusterRole", "", true, ""),
+	)
+
+	Describe("findDeletionNode", func() {
+		nodes := []v1alpha1.ResourceNode{
+			{ResourceRef: v1alpha1.ResourceRef{Kind: "ConfigMap", Namespace: "web", Name: "settings", Version: "v1"}},
+			{ResourceRef: v1alpha1.ResourceRef{Kind: "ConfigMap", Namespace: "api", Name: "settings", Version: "v1"}},
+			{ResourceRef: v1alpha1.ResourceRef{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "reader", Version: "v1"}},
+		}
+
+		It("should match the namespace exactly", func() {
+			node, err := findDeletionNode(nodes, "", "configmap", "api", "settings")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Namespace).To(Equal("api"))
+		})
+
+		It("should match an empty namespace only to cluster-scoped resources", func() {
+			node, err := findDeletionNode(nodes, "rbac.authorization.k8s.io", "ClusterRole", "", "reader")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Name).To(Equal("reader"))
+
+			_, err = findDeletionNode(nodes, "", "ConfigMap", "", "settings")
+			Expect(err).To(MatchError("resource ConfigMap settings is not cluster-scoped; set namespace to choose from: web, api"))
+		})
+
+		It("should list the namespaces a resource exists in when it isn't in the given one", func() {
+			_, err := findDeletionNode(nodes, "", "ConfigMap", "jobs", "settings")
+			Expect(err).To(MatchError("resource ConfigMap jobs/settings not found; it exists in namespaces: web, api"))
+
+			_, err = findDeletionNode(nodes, "", "Secret", "web", "settings")
+			Expect(err).To(MatchError("resource Secret web/settings not found"))
+		})
+	})
+})
@@ -0,0 +1,224 @@
+package argo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDiffContextLines is the number of unchanged lines shown around each change
+const DefaultDiffContextLines = 3
+
+// diffOp is a single line-level edit operation
+type diffOp struct {
+	kind byte // ' ' for equal, '-' for delete, '+' for insert
+	line string
+}
+
+// unifiedDiff renders a unified diff between two texts
+// Returns an empty string if the texts are identical
+func unifiedDiff(fromName, toName, from, to string, contextLines int) string {
+	if from == to {
+		return ""
+	}
+	if contextLines < 0 {
+		contextLines = DefaultDiffContextLines
+	}
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Walk the edit script and emit hunks of changes with surrounding context
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start >= len(ops) {
+			break
+		}
+
+		hunkStart := max(start-contextLines, 0)
+
+		// Extend the hunk until there are more than 2*context equal lines in a row
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*contextLines {
+				end = min(end+contextLines, len(ops))
+				break
+			}
+			end = run
+		}
+
+		writeHunk(&b, ops, hunkStart, end)
+		start = end
+	}
+
+	return b.String()
+}
+
+// writeHunk writes ops[start:end] as a single hunk with its header
+func writeHunk(b *strings.Builder, ops []diffOp, start, end int) {
+	// Line numbers are 1-based and count lines consumed before the hunk
+	fromLine, toLine := 1, 1
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			fromLine++
+		}
+		if op.kind != '-' {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			fromCount++
+		}
+		if op.kind != '-' {
+			toCount++
+		}
+	}
+
+	// An empty range starts at the line before it, per the unified diff format
+	if fromCount == 0 {
+		fromLine--
+	}
+	if toCount == 0 {
+		toLine--
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+	for _, op := range ops[start:end] {
+		b.WriteByte(op.kind)
+		b.WriteString(op.line)
+		b.WriteByte('\n')
+	}
+}
+
+// diffLines computes a minimal line-level edit script
+// It uses the linear-space variant of Myers' algorithm, so large manifests don't need an n*m table
+func diffLines(a, b []string) []diffOp {
+	return appendDiff(make([]diffOp, 0, len(a)+len(b)), a, b)
+}
+
+// appendDiff appends the edit script from a to b, splitting the problem at the middle snake
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	// Common leading and trailing lines are equal without searching
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{kind: '+', line: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{kind: '-', line: line})
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		ops = appendDiff(ops, a[:x], b[:y])
+		for _, line := range a[x:u] {
+			ops = append(ops, diffOp{kind: ' ', line: line})
+		}
+		ops = appendDiff(ops, a[u:], b[v:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	return ops
+}
+
+// middleSnake finds the run of equal lines in the middle of a shortest edit script from a to b
+// It searches forward from the start and backward from the end at once, and returns the run as a[x:u] == b[y:v]
+// a and b must be non-empty and differ in their first and last lines
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// forward[k] and backward[k] hold the furthest x reached on diagonal k = x - y, backward counting from the end
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x0 int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x0 = forward[offset+k+1]
+			} else {
+				x0 = forward[offset+k-1] + 1
+			}
+			y0 := x0 - k
+			x1, y1 := x0, y0
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[offset+k] = x1
+
+			// Diagonal k forward is diagonal delta-k backward
+			if back := delta - k; odd && back >= -(d-1) && back <= d-1 && x1+backward[offset+back] >= n {
+				return x0, y0, x1, y1
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x0 int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x0 = backward[offset+k+1]
+			} else {
+				x0 = backward[offset+k-1] + 1
+			}
+			y0 := x0 - k
+			x1, y1 := x0, y0
+			for x1 < n && y1 < m && a[n-1-x1] == b[m-1-y1] {
+				x1++
+				y1++
+			}
+			backward[offset+k] = x1
+
+			if fwd := delta - k; !odd && fwd >= -d && fwd <= d && x1+forward[offset+fwd] >= n {
+				return n - x1, m - y1, n - x0, m - y0
+			}
+		}
+	}
+
+	// Unreachable: the searches always meet within half the edit distance
+	return 0, 0, 0, 0
+}
+
+// splitLines splits text into lines, ignoring a single trailing newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,121 @@
+package argo
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	Describe("unifiedDiff", func() {
+		It("should return empty string for identical texts", func() {
+			Expect(unifiedDiff("a", "b", "x\ny\n", "x\ny\n", 3)).To(BeEmpty())
+		})
+
+		It("should render a single changed line with context", func() {
+			from := "a\nb\nc\nd\ne\n"
+			to := "a\nb\nC\nd\ne\n"
+
+			diff := unifiedDiff("live", "target", from, to, 1)
+			Expect(diff).To(Equal(strings.Join([]string{
+				"--- live",
+				"+++ target",
+				"@@ -2,3 +2,3 @@",
+				" b",
+				"-c",
+				"+C",
+				" d",
+				"",
+			}, "\n")))
+		})
+
+		It("should split distant changes into separate hunks", func() {
+			from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+			to := "1x\n2\n3\n4\n5\n6\n7\n8\n9x\n"
+
+			diff := unifiedDiff("live", "target", from, to, 1)
+			Expect(strings.Count(diff, "@@ -")).To(Equal(2))
+			Expect(diff).To(ContainSubstring("@@ -1,2 +1,2 @@"))
+			Expect(diff).To(ContainSubstring("@@ -8,2 +8,2 @@"))
+		})
+
+		It("should merge nearby changes into one hunk", func() {
+			from := "1\n2\n3\n4\n5\n"
+			to := "1x\n2\n3\n4x\n5\n"
+
+			diff := unifiedDiff("live", "target", from, to, 1)
+			Expect(strings.Count(diff, "@@ -")).To(Equal(1))
+			Expect(diff).To(ContainSubstring("@@ -1,5 +1,5 @@"))
+		})
+
+		It("should handle an empty source", func() {
+			diff := unifiedDiff("live", "target", "", "a\nb\n", 3)
+			Expect(diff).To(ContainSubstring("@@ -0,0 +1,2 @@"))
+			Expect(diff).To(ContainSubstring("+a\n+b\n"))
+		})
+
+		It("should handle an empty target", func() {
+			diff := unifiedDiff("live", "target", "a\n", "", 3)
+			Expect(diff).To(ContainSubstring("@@ -1,1 +0,0 @@"))
+			Expect(diff).To(ContainSubstring("-a\n"))
+		})
+	})
+
+	Describe("diffLines", func() {
+		It("should produce a minimal edit script", func() {
+			ops := diffLines([]string{"a", "b", "c"}, []string{"a", "c", "d"})
+
+			kinds := make([]string, 0, len(ops))
+			for _, op := range ops {
+				kinds = append(kinds, string(op.kind)+op.line)
+			}
+			Expect(kinds).To(Equal([]string{" a", "-b", " c", "+d"}))
+		})
+
+		It("should turn a into b with as few edits as a full comparison", func() {
+			a := strings.Split("a b c a b b a", " ")
+			b := strings.Split("c b a b a c", " ")
+			ops := diffLines(a, b)
+
+			var from, to []string
+			edits := 0
+			for _, op := range ops {
+				if op.kind != '+' {
+					from = append(from, op.line)
+				}
+				if op.kind != '-' {
+					to = append(to, op.line)
+				}
+				if op.kind != ' ' {
+					edits++
+				}
+			}
+			Expect(from).To(Equal(a))
+			Expect(to).To(Equal(b))
+			Expect(edits).To(Equal(5))
+		})
+
+		It("should diff large manifests", func() {
+			a := make([]string, 0, 100000)
+			b := make([]string, 0, 100000)
+			for i := range 100000 {
+				a = append(a, fmt.Sprintf("key%d: value", i))
+				if i%10000 == 5000 {
+					b = append(b, fmt.Sprintf("key%d: changed", i))
+					continue
+				}
+				b = append(b, fmt.Sprintf("key%d: value", i))
+			}
+
+			edits := 0
+			for _, op := range diffLines(a, b) {
+				if op.kind != ' ' {
+					edits++
+				}
+			}
+			Expect(edits).To(Equal(20))
+		})
+	})
+})
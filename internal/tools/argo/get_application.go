@@ -0,0 +1,248 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetApplicationInput defines the input parameters for getting a single Argo application
+type GetApplicationInput struct {
+	Name         string `json:"name" jsonschema:"application name"`
+	AppNamespace string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Live         bool   `json:"live,omitempty" jsonschema:"skip the application cache and read live data from Argo CD"`
+}
+
+// GetApplicationOutput defines the output structure for getting a single Argo application
+type GetApplicationOutput struct {
+	Application ApplicationDetail `json:"application" jsonschema:"typed view of the application"`
+	FromCache   bool              `json:"fromCache" jsonschema:"true if the data was served from the application cache"`
+}
+
+// ApplicationDetail is a typed view of a single Argo CD application
+type ApplicationDetail struct {
+	Name           string                  `json:"name"`
+	Namespace      string                  `json:"namespace"`
+	Project        string                  `json:"project"`
+	Sources        []ApplicationSourceInfo `json:"sources"`
+	Destination    DestinationInfo         `json:"destination"`
+	Sync           SyncInfo                `json:"sync"`
+	Health         HealthInfo              `json:"health"`
+	AutoSync       bool                    `json:"autoSync"`
+	OperationState *OperationStateInfo     `json:"operationState,omitempty"`
+	Conditions     []ConditionInfo         `json:"conditions,omitempty"`
+	ReconciledAt   string                  `json:"reconciledAt,omitempty"`
+}
+
+// ApplicationSourceInfo describes one source of an application
+type ApplicationSourceInfo struct {
+	RepoURL        string `json:"repoURL"`
+	Path           string `json:"path,omitempty"`
+	Chart          string `json:"chart,omitempty"`
+	TargetRevision string `json:"targetRevision,omitempty"`
+	Ref            string `json:"ref,omitempty"`
+	Type           string `json:"type,omitempty"`
+}
+
+// DestinationInfo describes where an application is deployed
+type DestinationInfo struct {
+	Server    string `json:"server,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SyncInfo describes the sync status of an application
+type SyncInfo struct {
+	Status    string   `json:"status"`
+	Revision  string   `json:"revision,omitempty"`
+	Revisions []string `json:"revisions,omitempty"`
+}
+
+// HealthInfo describes the health status of an application or resource
+type HealthInfo struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// OperationStateInfo describes the current or last operation of an application
+type OperationStateInfo struct {
+	Phase      string `json:"phase"`
+	Message    string `json:"message,omitempty"`
+	StartedAt  string `json:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+	Revision   string `json:"revision,omitempty"`
+	RetryCount int64  `json:"retryCount,omitempty"`
+}
+
+// ConditionInfo describes an application condition such as a sync or comparison error
+type ConditionInfo struct {
+	Type               string `json:"type"`
+	Message            string `json:"message"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// NewGetApplicationHandler creates a GetApplication handler with the provided AppContext
+func NewGetApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetApplicationInput) (*mcp.CallToolResult, GetApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetApplicationInput) (*mcp.CallToolResult, GetApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_get_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_application completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetApplicationOutput{}, fmt.Errorf("application name is required")
+		}
+
+		app, fromCache, err := loadApplication(ctx, appCtx, input.Name, input.AppNamespace, input.Live)
+		if err != nil {
+			return nil, GetApplicationOutput{}, err
+		}
+		l.Infow("Loaded application", "name", input.Name, "from_cache", fromCache)
+
+		return nil, GetApplicationOutput{
+			Application: summarizeApplication(app),
+			FromCache:   fromCache,
+		}, nil
+	}
+}
+
+// loadApplication returns an application from the cache, or from ArgoCD on a cache miss or when live is set
+// The boolean result reports whether the application was served from the cache
+func loadApplication(ctx context.Context, appCtx *appcontext.AppContext, name, appNamespace string, live bool) (*v1alpha1.Application, bool, error) {
+	if !live {
+		if cachedApp := appCtx.FindCachedApplication(name, appNamespace); cachedApp != nil {
+			return cachedApp, true, nil
+		}
+	}
+
+	app, err := getApplication(ctx, appCtx, name, appNamespace)
+	if err != nil {
+		return nil, false, err
+	}
+	return app, false, nil
+}
+
+// getApplication fetches a single application directly from ArgoCD
+func getApplication(ctx context.Context, appCtx *appcontext.AppContext, name, appNamespace string) (*v1alpha1.Application, error) {
+	conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application client: %w", err)
+	}
+	defer conn.Close()
+
+	query := &application.ApplicationQuery{Name: &name}
+	if appNamespace != "" {
+		query.AppNamespace = &appNamespace
+	}
+
+	app, err := appClient.Get(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application %q: %w", name, err)
+	}
+
+	return app, nil
+}
+
+// summarizeApplication converts a raw application into its typed view
+func summarizeApplication(app *v1alpha1.Application) ApplicationDetail {
+	detail := ApplicationDetail{
+		Name:      app.Name,
+		Namespace: app.Namespace,
+		Project:   app.Spec.Project,
+		Sources:   make([]ApplicationSourceInfo, 0),
+		Destination: DestinationInfo{
+			Server:    app.Spec.Destination.Server,
+			Name:      app.Spec.Destination.Name,
+			Namespace: app.Spec.Destination.Namespace,
+		},
+		Sync: SyncInfo{
+			Status:    string(app.Status.Sync.Status),
+			Revision:  app.Status.Sync.Revision,
+			Revisions: app.Status.Sync.Revisions,
+		},
+		Health: HealthInfo{
+			Status:  string(app.Status.Health.Status),
+			Message: app.Status.Health.Message,
+		},
+		AutoSync:     app.Spec.SyncPolicy != nil && app.Spec.SyncPolicy.Automated != nil,
+		ReconciledAt: formatTime(app.Status.ReconciledAt),
+	}
+
+	// GetSources handles both single-source and multi-source applications
+	sourceTypes := app.Status.SourceTypes
+	for i, source := range app.Spec.GetSources() {
+		info := summarizeSource(source)
+		if i < len(sourceTypes) {
+			info.Type = string(sourceTypes[i])
+		} else if i == 0 && app.Status.SourceType != "" {
+			info.Type = string(app.Status.SourceType)
+		}
+		detail.Sources = append(detail.Sources, info)
+	}
+
+	if app.Status.OperationState != nil {
+		detail.OperationState = summarizeOperationState(app.Status.OperationState)
+	}
+
+	for _, condition := range app.Status.Conditions {
+		detail.Conditions = append(detail.Conditions, ConditionInfo{
+			Type:               string(condition.Type),
+			Message:            condition.Message,
+			LastTransitionTime: formatTime(condition.LastTransitionTime),
+		})
+	}
+
+	return detail
+}
+
+// summarizeSource converts an application source into its typed view
+func summarizeSource(source v1alpha1.ApplicationSource) ApplicationSourceInfo {
+	return ApplicationSourceInfo{
+		RepoURL:        source.RepoURL,
+		Path:           source.Path,
+		Chart:          source.Chart,
+		TargetRevision: source.TargetRevision,
+		Ref:            source.Ref,
+	}
+}
+
+// summarizeOperationState converts an operation state into its typed view
+func summarizeOperationState(state *v1alpha1.OperationState) *OperationStateInfo {
+	info := &OperationStateInfo{
+		Phase:      string(state.Phase),
+		Message:    state.Message,
+		StartedAt:  formatTime(&state.StartedAt),
+		FinishedAt: formatTime(state.FinishedAt),
+		RetryCount: state.RetryCount,
+	}
+	if state.SyncResult != nil {
+		info.Revision = state.SyncResult.Revision
+	}
+	return info
+}
+
+// formatTime renders a Kubernetes timestamp as RFC3339, or an empty string if unset
+func formatTime(t *metav1.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatGoTime(t.Time)
+}
+
+// formatGoTime renders a timestamp as RFC3339, or an empty string if unset
+func formatGoTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
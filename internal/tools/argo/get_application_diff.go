@@ -0,0 +1,226 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ResourceDiffStatusSynced means live and target state match
+	ResourceDiffStatusSynced = "Synced"
+
+	// ResourceDiffStatusModified means the live state differs from the target state
+	ResourceDiffStatusModified = "Modified"
+
+	// ResourceDiffStatusMissing means the resource exists in git but not in the cluster
+	ResourceDiffStatusMissing = "Missing"
+
+	// ResourceDiffStatusExtra means the resource exists in the cluster but not in git and requires pruning
+	ResourceDiffStatusExtra = "Extra"
+)
+
+// noisyMetadataFields are server-populated metadata fields stripped before diffing
+var noisyMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"generation",
+	"creationTimestamp",
+	"selfLink",
+}
+
+// noisyAnnotations are annotations stripped before diffing
+var noisyAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// GetApplicationDiffInput defines the input parameters for diffing an application's managed resources
+type GetApplicationDiffInput struct {
+	Name         string `json:"name" jsonschema:"application name"`
+	AppNamespace string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	OnlyChanged  bool   `json:"onlyChanged,omitempty" jsonschema:"return only resources whose live state differs from the target state"`
+	ContextLines *int   `json:"contextLines,omitempty" jsonschema:"optional number of unchanged lines around each change (default 3)"`
+}
+
+// GetApplicationDiffOutput defines the output structure for diffing an application's managed resources
+type GetApplicationDiffOutput struct {
+	Total     int                `json:"total" jsonschema:"number of managed resources"`
+	Changed   int                `json:"changed" jsonschema:"number of managed resources that differ"`
+	Resources []ResourceDiffInfo `json:"resources" jsonschema:"per-resource diff between live and target state"`
+}
+
+// ResourceDiffInfo describes the difference between live and target state of one resource
+type ResourceDiffInfo struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Hook      bool   `json:"hook,omitempty"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+// NewGetApplicationDiffHandler creates a GetApplicationDiff handler with the provided AppContext
+func NewGetApplicationDiffHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetApplicationDiffInput) (*mcp.CallToolResult, GetApplicationDiffOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetApplicationDiffInput) (*mcp.CallToolResult, GetApplicationDiffOutput, error) {
+		l := log.Logger().With("component", "argocd_get_application_diff")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_application_diff completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetApplicationDiffOutput{}, fmt.Errorf("application name is required")
+		}
+
+		contextLines := DefaultDiffContextLines
+		if input.ContextLines != nil {
+			contextLines = *input.ContextLines
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetApplicationDiffOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ResourcesQuery{ApplicationName: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+
+		managed, err := appClient.ManagedResources(ctx, query)
+		if err != nil {
+			return nil, GetApplicationDiffOutput{}, fmt.Errorf("failed to get managed resources for application %q: %w", input.Name, err)
+		}
+		l.Infow("Fetched managed resources", "name", input.Name, "count", len(managed.Items))
+
+		output := GetApplicationDiffOutput{
+			Total:     len(managed.Items),
+			Resources: make([]ResourceDiffInfo, 0),
+		}
+		for _, item := range managed.Items {
+			info, err := diffManagedResource(item, contextLines)
+			if err != nil {
+				return nil, GetApplicationDiffOutput{}, err
+			}
+			if info.Status != ResourceDiffStatusSynced {
+				output.Changed++
+			} else if input.OnlyChanged {
+				continue
+			}
+			output.Resources = append(output.Resources, info)
+		}
+
+		return nil, output, nil
+	}
+}
+
+// diffManagedResource normalizes the live and target state of a resource and diffs them
+func diffManagedResource(item *v1alpha1.ResourceDiff, contextLines int) (ResourceDiffInfo, error) {
+	info := ResourceDiffInfo{
+		Group:     item.Group,
+		Kind:      item.Kind,
+		Namespace: item.Namespace,
+		Name:      item.Name,
+		Hook:      item.Hook,
+	}
+
+	// Prefer the states Argo CD itself compares, which already honour ignoreDifferences
+	live := item.NormalizedLiveState
+	if isEmptyState(live) {
+		live = item.LiveState
+	}
+	target := item.PredictedLiveState
+	if isEmptyState(target) {
+		target = item.TargetState
+	}
+
+	liveYAML, err := normalizeState(live)
+	if err != nil {
+		return info, fmt.Errorf("failed to normalize live state of %s/%s: %w", item.Kind, item.Name, err)
+	}
+	targetYAML, err := normalizeState(target)
+	if err != nil {
+		return info, fmt.Errorf("failed to normalize target state of %s/%s: %w", item.Kind, item.Name, err)
+	}
+
+	info.Diff = unifiedDiff("live", "target", liveYAML, targetYAML, contextLines)
+
+	switch {
+	case liveYAML == "" && targetYAML != "":
+		info.Status = ResourceDiffStatusMissing
+	case liveYAML != "" && targetYAML == "":
+		info.Status = ResourceDiffStatusExtra
+	case info.Diff != "":
+		info.Status = ResourceDiffStatusModified
+	default:
+		info.Status = ResourceDiffStatusSynced
+	}
+
+	return info, nil
+}
+
+// normalizeState strips server-populated fields from a JSON manifest and renders it as YAML
+// Returns an empty string for an absent state
+func normalizeState(state string) (string, error) {
+	if isEmptyState(state) {
+		return "", nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(state), &obj); err != nil {
+		return "", err
+	}
+	if obj == nil {
+		return "", nil
+	}
+
+	stripNoisyFields(obj)
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// stripNoisyFields removes status and server-populated metadata from a manifest in place
+func stripNoisyFields(obj map[string]interface{}) {
+	delete(obj, "status")
+
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, field := range noisyMetadataFields {
+		delete(metadata, field)
+	}
+
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, annotation := range noisyAnnotations {
+		delete(annotations, annotation)
+	}
+	if len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+}
+
+// isEmptyState reports whether a serialized state represents an absent object
+func isEmptyState(state string) bool {
+	return state == "" || state == "null"
+}
@@ -0,0 +1,111 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("Get Application Diff", func() {
+	const liveState = `{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {
+			"name": "web",
+			"namespace": "guestbook",
+			"uid": "1234",
+			"resourceVersion": "99",
+			"generation": 4,
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+			"managedFields": [{"manager": "argocd-controller"}],
+			"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+		},
+		"spec": {"replicas": 2},
+		"status": {"readyReplicas": 2}
+	}`
+
+	const targetState = `{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {"name": "web", "namespace": "guestbook"},
+		"spec": {"replicas": 3}
+	}`
+
+	Describe("normalizeState", func() {
+		It("should return empty string for absent states", func() {
+			for _, state := range []string{"", "null"} {
+				normalized, err := normalizeState(state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(normalized).To(BeEmpty())
+			}
+		})
+
+		It("should strip status and server-populated metadata", func() {
+			normalized, err := normalizeState(liveState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(normalized).To(ContainSubstring("replicas: 2"))
+			Expect(normalized).To(ContainSubstring("name: web"))
+			Expect(normalized).NotTo(ContainSubstring("status"))
+			Expect(normalized).NotTo(ContainSubstring("managedFields"))
+			Expect(normalized).NotTo(ContainSubstring("resourceVersion"))
+			Expect(normalized).NotTo(ContainSubstring("uid"))
+			Expect(normalized).NotTo(ContainSubstring("annotations"))
+		})
+
+		It("should return an error for invalid JSON", func() {
+			_, err := normalizeState("{not json")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("diffManagedResource", func() {
+		DescribeTable("status detection",
+			func(live, target string, expectedStatus string, expectDiff bool) {
+				item := &v1alpha1.ResourceDiff{
+					Group:               "apps",
+					Kind:                "Deployment",
+					Namespace:           "guestbook",
+					Name:                "web",
+					NormalizedLiveState: live,
+					PredictedLiveState:  target,
+				}
+
+				info, err := diffManagedResource(item, DefaultDiffContextLines)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Status).To(Equal(expectedStatus))
+				Expect(info.Kind).To(Equal("Deployment"))
+				if expectDiff {
+					Expect(info.Diff).NotTo(BeEmpty())
+				} else {
+					Expect(info.Diff).To(BeEmpty())
+				}
+			},
+			Entry("modified resource", liveState, targetState, ResourceDiffStatusModified, true),
+			Entry("synced resource ignores noisy fields", liveState, `{
+				"apiVersion": "apps/v1",
+				"kind": "Deployment",
+				"metadata": {"name": "web", "namespace": "guestbook"},
+				"spec": {"replicas": 2}
+			}`, ResourceDiffStatusSynced, false),
+			Entry("missing from cluster", "null", targetState, ResourceDiffStatusMissing, true),
+			Entry("extra resource requiring pruning", liveState, "", ResourceDiffStatusExtra, true),
+		)
+
+		It("should fall back to raw live and target state", func() {
+			item := &v1alpha1.ResourceDiff{
+				Kind:        "Deployment",
+				Name:        "web",
+				LiveState:   liveState,
+				TargetState: targetState,
+			}
+
+			info, err := diffManagedResource(item, DefaultDiffContextLines)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Status).To(Equal(ResourceDiffStatusModified))
+			Expect(info.Diff).To(ContainSubstring("-  replicas: 2"))
+			Expect(info.Diff).To(ContainSubstring("+  replicas: 3"))
+		})
+	})
+})
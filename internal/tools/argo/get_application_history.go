@@ -0,0 +1,231 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultHistoryLimit is the number of history entries returned when no limit is given
+const DefaultHistoryLimit = 10
+
+// GetApplicationHistoryInput defines the input parameters for getting an application's deployment history
+type GetApplicationHistoryInput struct {
+	Name                string `json:"name" jsonschema:"application name"`
+	AppNamespace        string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Limit               int    `json:"limit,omitempty" jsonschema:"optional maximum number of entries, newest first (default 10)"`
+	Cached              bool   `json:"cached,omitempty" jsonschema:"serve the history from the application cache, which can be up to an hour old and miss recent deployments"`
+	SkipMetadata        bool   `json:"skipMetadata,omitempty" jsonschema:"skip enriching git revisions with author, date and commit message"`
+	IncludeChartDetails bool   `json:"includeChartDetails,omitempty" jsonschema:"enrich Helm chart revisions with chart description, home and maintainers"`
+}
+
+// GetApplicationHistoryOutput defines the output structure for getting an application's deployment history
+type GetApplicationHistoryOutput struct {
+	Total     int            `json:"total" jsonschema:"total number of history entries recorded for the application"`
+	Entries   []HistoryEntry `json:"entries" jsonschema:"deployment history entries, newest first"`
+	FromCache bool           `json:"fromCache" jsonschema:"true if the history was served from the application cache"`
+}
+
+// HistoryEntry is a typed view of a single deployment in an application's history
+type HistoryEntry struct {
+	ID              int64               `json:"id"`
+	DeployedAt      string              `json:"deployedAt,omitempty"`
+	DeployStartedAt string              `json:"deployStartedAt,omitempty"`
+	InitiatedBy     string              `json:"initiatedBy,omitempty"`
+	Sources         []HistorySourceInfo `json:"sources"`
+}
+
+// HistorySourceInfo describes the revision deployed from one source, with optional metadata
+type HistorySourceInfo struct {
+	Source        ApplicationSourceInfo `json:"source"`
+	Revision      string                `json:"revision,omitempty"`
+	Metadata      *RevisionInfo         `json:"metadata,omitempty"`
+	ChartDetails  *ChartInfo            `json:"chartDetails,omitempty"`
+	MetadataError string                `json:"metadataError,omitempty"`
+}
+
+// RevisionInfo describes a git commit
+type RevisionInfo struct {
+	Author  string   `json:"author,omitempty"`
+	Date    string   `json:"date,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// ChartInfo describes a Helm chart version
+type ChartInfo struct {
+	Description string   `json:"description,omitempty"`
+	Home        string   `json:"home,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+}
+
+// NewGetApplicationHistoryHandler creates a GetApplicationHistory handler with the provided AppContext
+func NewGetApplicationHistoryHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetApplicationHistoryInput) (*mcp.CallToolResult, GetApplicationHistoryOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetApplicationHistoryInput) (*mcp.CallToolResult, GetApplicationHistoryOutput, error) {
+		l := log.Logger().With("component", "argocd_get_application_history")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_application_history completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetApplicationHistoryOutput{}, fmt.Errorf("application name is required")
+		}
+
+		limit := input.Limit
+		if limit <= 0 {
+			limit = DefaultHistoryLimit
+		}
+
+		// History is read live by default, since the question is usually what was deployed recently
+		app, fromCache, err := loadApplication(ctx, appCtx, input.Name, input.AppNamespace, !input.Cached)
+		if err != nil {
+			return nil, GetApplicationHistoryOutput{}, err
+		}
+
+		entries := summarizeHistory(app.Status.History, limit)
+		l.Infow("Loaded application history", "name", input.Name, "from_cache", fromCache, "total", len(app.Status.History), "returned", len(entries))
+
+		if input.SkipMetadata && !input.IncludeChartDetails {
+			return nil, GetApplicationHistoryOutput{
+				Total:     len(app.Status.History),
+				Entries:   entries,
+				FromCache: fromCache,
+			}, nil
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetApplicationHistoryOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		for i := range entries {
+			for j := range entries[i].Sources {
+				source := &entries[i].Sources[j]
+				if source.Revision == "" {
+					continue
+				}
+
+				metadataQuery := &application.RevisionMetadataQuery{
+					Name:         &app.Name,
+					AppNamespace: &app.Namespace,
+					Revision:     &source.Revision,
+					SourceIndex:  int32Ptr(int32(j)),
+					VersionId:    int32Ptr(int32(entries[i].ID)),
+				}
+
+				// Chart revisions are versions rather than commits, so they only have chart details
+				if source.Source.Chart != "" {
+					if !input.IncludeChartDetails {
+						continue
+					}
+					details, err := appClient.RevisionChartDetails(ctx, metadataQuery)
+					if err != nil {
+						l.Warnw("Failed to get chart details", "revision", source.Revision, "error", err)
+						source.MetadataError = err.Error()
+						continue
+					}
+					source.ChartDetails = &ChartInfo{
+						Description: details.Description,
+						Home:        details.Home,
+						Maintainers: details.Maintainers,
+					}
+					continue
+				}
+
+				if input.SkipMetadata {
+					continue
+				}
+				metadata, err := appClient.RevisionMetadata(ctx, metadataQuery)
+				if err != nil {
+					l.Warnw("Failed to get revision metadata", "revision", source.Revision, "error", err)
+					source.MetadataError = err.Error()
+					continue
+				}
+				source.Metadata = &RevisionInfo{
+					Author:  metadata.Author,
+					Date:    formatTime(&metadata.Date),
+					Message: metadata.Message,
+					Tags:    metadata.Tags,
+				}
+			}
+		}
+
+		return nil, GetApplicationHistoryOutput{
+			Total:     len(app.Status.History),
+			Entries:   entries,
+			FromCache: fromCache,
+		}, nil
+	}
+}
+
+// summarizeHistory converts revision history into typed entries, newest first, capped at limit
+func summarizeHistory(history v1alpha1.RevisionHistories, limit int) []HistoryEntry {
+	sorted := make(v1alpha1.RevisionHistories, len(history))
+	copy(sorted, history)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID > sorted[j].ID
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	entries := make([]HistoryEntry, 0, len(sorted))
+	for _, h := range sorted {
+		entries = append(entries, summarizeHistoryEntry(h))
+	}
+
+	return entries
+}
+
+// summarizeHistoryEntry converts a single revision history entry
+func summarizeHistoryEntry(h v1alpha1.RevisionHistory) HistoryEntry {
+	entry := HistoryEntry{
+		ID:              h.ID,
+		DeployedAt:      formatTime(&h.DeployedAt),
+		DeployStartedAt: formatTime(h.DeployStartedAt),
+		InitiatedBy:     formatInitiator(h.InitiatedBy),
+		Sources:         make([]HistorySourceInfo, 0),
+	}
+
+	// Multi-source entries record one revision per source, single-source entries just one
+	if len(h.Sources) > 0 {
+		for i, source := range h.Sources {
+			info := HistorySourceInfo{Source: summarizeSource(source)}
+			if i < len(h.Revisions) {
+				info.Revision = h.Revisions[i]
+			}
+			entry.Sources = append(entry.Sources, info)
+		}
+	} else {
+		entry.Sources = append(entry.Sources, HistorySourceInfo{
+			Source:   summarizeSource(h.Source),
+			Revision: h.Revision,
+		})
+	}
+
+	return entry
+}
+
+// formatInitiator describes who started an operation
+func formatInitiator(initiator v1alpha1.OperationInitiator) string {
+	if initiator.Automated {
+		return "automated"
+	}
+	return initiator.Username
+}
+
+// int32Ptr returns a pointer to the given value
+func int32Ptr(v int32) *int32 {
+	return &v
+}
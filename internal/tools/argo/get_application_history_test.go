@@ -0,0 +1,87 @@
+package argo
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Get Application History", func() {
+	Describe("summarizeHistory", func() {
+		deployedAt := metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+		history := v1alpha1.RevisionHistories{
+			{
+				ID:         1,
+				Revision:   "aaa111",
+				DeployedAt: deployedAt,
+				Source:     v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/repo", Path: "guestbook"},
+			},
+			{
+				ID:          3,
+				Revisions:   []string{"1.2.3", "ccc333"},
+				DeployedAt:  deployedAt,
+				InitiatedBy: v1alpha1.OperationInitiator{Automated: true},
+				Sources: v1alpha1.ApplicationSources{
+					{RepoURL: "https://charts.example.com", Chart: "guestbook", TargetRevision: "1.2.3"},
+					{RepoURL: "https://github.com/example/values", Ref: "values"},
+				},
+			},
+			{
+				ID:          2,
+				Revision:    "bbb222",
+				DeployedAt:  deployedAt,
+				InitiatedBy: v1alpha1.OperationInitiator{Username: "alice"},
+				Source:      v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/repo", Path: "guestbook"},
+			},
+		}
+
+		It("should return entries newest first", func() {
+			entries := summarizeHistory(history, DefaultHistoryLimit)
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[0].ID).To(Equal(int64(3)))
+			Expect(entries[1].ID).To(Equal(int64(2)))
+			Expect(entries[2].ID).To(Equal(int64(1)))
+			Expect(entries[2].DeployedAt).To(Equal("2024-05-01T12:00:00Z"))
+		})
+
+		It("should not reorder the original history", func() {
+			summarizeHistory(history, DefaultHistoryLimit)
+			Expect(history[0].ID).To(Equal(int64(1)))
+		})
+
+		It("should cap entries at the limit", func() {
+			entries := summarizeHistory(history, 2)
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[1].ID).To(Equal(int64(2)))
+		})
+
+		It("should describe single-source entries", func() {
+			entries := summarizeHistory(history, DefaultHistoryLimit)
+			Expect(entries[1].InitiatedBy).To(Equal("alice"))
+			Expect(entries[1].Sources).To(HaveLen(1))
+			Expect(entries[1].Sources[0].Revision).To(Equal("bbb222"))
+			Expect(entries[1].Sources[0].Source.Path).To(Equal("guestbook"))
+		})
+
+		It("should pair multi-source entries with their revisions", func() {
+			entries := summarizeHistory(history, DefaultHistoryLimit)
+			Expect(entries[0].InitiatedBy).To(Equal("automated"))
+			Expect(entries[0].Sources).To(HaveLen(2))
+			Expect(entries[0].Sources[0].Source.Chart).To(Equal("guestbook"))
+			Expect(entries[0].Sources[0].Revision).To(Equal("1.2.3"))
+			Expect(entries[0].Sources[1].Source.Ref).To(Equal("values"))
+			Expect(entries[0].Sources[1].Revision).To(Equal("ccc333"))
+		})
+
+		It("should return an empty slice for no history", func() {
+			entries := summarizeHistory(nil, DefaultHistoryLimit)
+			Expect(entries).NotTo(BeNil())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,136 @@
+package argo
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Get Application", func() {
+	Describe("summarizeApplication", func() {
+		var app *v1alpha1.Application
+
+		BeforeEach(func() {
+			app = &v1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "guestbook",
+					Namespace: "argocd",
+				},
+				Spec: v1alpha1.ApplicationSpec{
+					Project: "default",
+					Source: &v1alpha1.ApplicationSource{
+						RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+						Path:           "guestbook",
+						TargetRevision: "HEAD",
+					},
+					Destination: v1alpha1.ApplicationDestination{
+						Server:    "https://kubernetes.default.svc",
+						Namespace: "guestbook",
+					},
+				},
+				Status: v1alpha1.ApplicationStatus{
+					Sync: v1alpha1.SyncStatus{
+						Status:   v1alpha1.SyncStatusCodeOutOfSync,
+						Revision: "abc123",
+					},
+					Health: v1alpha1.HealthStatus{
+						Status:  "Degraded",
+						Message: "deployment is not ready",
+					},
+					SourceType: v1alpha1.ApplicationSourceTypeDirectory,
+				},
+			}
+		})
+
+		It("should map identity, destination, sync and health", func() {
+			detail := summarizeApplication(app)
+
+			Expect(detail.Name).To(Equal("guestbook"))
+			Expect(detail.Namespace).To(Equal("argocd"))
+			Expect(detail.Project).To(Equal("default"))
+			Expect(detail.Destination.Server).To(Equal("https://kubernetes.default.svc"))
+			Expect(detail.Destination.Namespace).To(Equal("guestbook"))
+			Expect(detail.Sync.Status).To(Equal("OutOfSync"))
+			Expect(detail.Sync.Revision).To(Equal("abc123"))
+			Expect(detail.Health.Status).To(Equal("Degraded"))
+			Expect(detail.Health.Message).To(Equal("deployment is not ready"))
+			Expect(detail.AutoSync).To(BeFalse())
+			Expect(detail.OperationState).To(BeNil())
+		})
+
+		It("should map a single source with its type", func() {
+			detail := summarizeApplication(app)
+
+			Expect(detail.Sources).To(HaveLen(1))
+			Expect(detail.Sources[0].RepoURL).To(Equal("https://github.com/argoproj/argocd-example-apps"))
+			Expect(detail.Sources[0].Path).To(Equal("guestbook"))
+			Expect(detail.Sources[0].TargetRevision).To(Equal("HEAD"))
+			Expect(detail.Sources[0].Type).To(Equal("Directory"))
+		})
+
+		It("should map multiple sources", func() {
+			app.Spec.Source = nil
+			app.Spec.Sources = v1alpha1.ApplicationSources{
+				{RepoURL: "https://charts.example.com", Chart: "nginx", TargetRevision: "1.2.3"},
+				{RepoURL: "https://github.com/example/values", Ref: "values"},
+			}
+			app.Status.SourceTypes = []v1alpha1.ApplicationSourceType{
+				v1alpha1.ApplicationSourceTypeHelm,
+				v1alpha1.ApplicationSourceTypeDirectory,
+			}
+
+			detail := summarizeApplication(app)
+
+			Expect(detail.Sources).To(HaveLen(2))
+			Expect(detail.Sources[0].Chart).To(Equal("nginx"))
+			Expect(detail.Sources[0].Type).To(Equal("Helm"))
+			Expect(detail.Sources[1].Ref).To(Equal("values"))
+			Expect(detail.Sources[1].Type).To(Equal("Directory"))
+		})
+
+		It("should report auto-sync when automated policy is set", func() {
+			app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{Automated: &v1alpha1.SyncPolicyAutomated{}}
+
+			detail := summarizeApplication(app)
+			Expect(detail.AutoSync).To(BeTrue())
+		})
+
+		It("should map operation state and conditions", func() {
+			started := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+			app.Status.OperationState = &v1alpha1.OperationState{
+				Phase:      "Running",
+				Message:    "waiting for healthy state",
+				StartedAt:  started,
+				SyncResult: &v1alpha1.SyncOperationResult{Revision: "def456"},
+			}
+			app.Status.Conditions = []v1alpha1.ApplicationCondition{
+				{Type: v1alpha1.ApplicationConditionSyncError, Message: "one or more objects failed to apply"},
+			}
+
+			detail := summarizeApplication(app)
+
+			Expect(detail.OperationState).NotTo(BeNil())
+			Expect(detail.OperationState.Phase).To(Equal("Running"))
+			Expect(detail.OperationState.StartedAt).To(Equal("2024-01-02T03:04:05Z"))
+			Expect(detail.OperationState.FinishedAt).To(BeEmpty())
+			Expect(detail.OperationState.Revision).To(Equal("def456"))
+			Expect(detail.Conditions).To(HaveLen(1))
+			Expect(detail.Conditions[0].Type).To(Equal("SyncError"))
+		})
+	})
+
+	Describe("formatTime", func() {
+		It("should return empty string for nil", func() {
+			Expect(formatTime(nil)).To(BeEmpty())
+		})
+
+		It("should format as RFC3339 in UTC", func() {
+			t := metav1.NewTime(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+			Expect(formatTime(&t)).To(Equal("2024-05-06T07:08:09Z"))
+		})
+	})
+})
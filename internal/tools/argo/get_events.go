@@ -0,0 +1,233 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetEventsInput defines the input parameters for getting Kubernetes events of an application or resource
+type GetEventsInput struct {
+	Name              string `json:"name" jsonschema:"application name"`
+	AppNamespace      string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	ResourceGroup     string `json:"resourceGroup,omitempty" jsonschema:"optional API group of a managed resource (empty for core resources)"`
+	ResourceKind      string `json:"resourceKind,omitempty" jsonschema:"optional kind of a managed resource; when set with resourceName, events of that resource are returned instead of the application's"`
+	ResourceNamespace string `json:"resourceNamespace,omitempty" jsonschema:"optional namespace of a managed resource"`
+	ResourceName      string `json:"resourceName,omitempty" jsonschema:"optional name of a managed resource"`
+	WarningOnly       bool   `json:"warningOnly,omitempty" jsonschema:"return only Warning events"`
+	SinceMinutes      int    `json:"sinceMinutes,omitempty" jsonschema:"optional time window: only events last seen within this many minutes"`
+}
+
+// GetEventsOutput defines the output structure for getting Kubernetes events
+type GetEventsOutput struct {
+	Count  int         `json:"count" jsonschema:"number of deduplicated events returned"`
+	Events []EventInfo `json:"events" jsonschema:"events sorted newest first"`
+}
+
+// EventInfo is a typed view of a deduplicated Kubernetes event
+type EventInfo struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count"`
+	Object    string `json:"object"`
+	Source    string `json:"source,omitempty"`
+	FirstSeen string `json:"firstSeen,omitempty"`
+	LastSeen  string `json:"lastSeen,omitempty"`
+}
+
+// NewGetEventsHandler creates a GetEvents handler with the provided AppContext
+func NewGetEventsHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetEventsInput) (*mcp.CallToolResult, GetEventsOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetEventsInput) (*mcp.CallToolResult, GetEventsOutput, error) {
+		l := log.Logger().With("component", "argocd_get_events")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_events completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetEventsOutput{}, fmt.Errorf("application name is required")
+		}
+		if (input.ResourceKind == "") != (input.ResourceName == "") {
+			return nil, GetEventsOutput{}, fmt.Errorf("resourceKind and resourceName must be set together")
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetEventsOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ApplicationResourceEventsQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+
+		// Events of a managed resource are looked up by UID, which we resolve from the resource tree
+		if input.ResourceKind != "" {
+			treeQuery := &application.ResourcesQuery{ApplicationName: &input.Name}
+			if input.AppNamespace != "" {
+				treeQuery.AppNamespace = &input.AppNamespace
+			}
+			tree, err := appClient.ResourceTree(ctx, treeQuery)
+			if err != nil {
+				return nil, GetEventsOutput{}, fmt.Errorf("failed to get resource tree for application %q: %w", input.Name, err)
+			}
+
+			node := findResourceNode(append(tree.Nodes, tree.OrphanedNodes...), input.ResourceGroup, input.ResourceKind, input.ResourceNamespace, input.ResourceName)
+			if node == nil {
+				return nil, GetEventsOutput{}, fmt.Errorf("resource %s/%s/%s/%s not found in application %q", input.ResourceGroup, input.ResourceKind, input.ResourceNamespace, input.ResourceName, input.Name)
+			}
+			query.ResourceName = &node.Name
+			query.ResourceNamespace = &node.Namespace
+			query.ResourceUID = &node.UID
+		}
+
+		eventList, err := appClient.ListResourceEvents(ctx, query)
+		if err != nil {
+			return nil, GetEventsOutput{}, fmt.Errorf("failed to list events for application %q: %w", input.Name, err)
+		}
+		l.Infow("Fetched events", "name", input.Name, "count", len(eventList.Items))
+
+		var since time.Time
+		if input.SinceMinutes > 0 {
+			since = time.Now().Add(-time.Duration(input.SinceMinutes) * time.Minute)
+		}
+
+		events := summarizeEvents(eventList.Items, input.WarningOnly, since)
+		return nil, GetEventsOutput{
+			Count:  len(events),
+			Events: events,
+		}, nil
+	}
+}
+
+// findResourceNode finds a node in the resource tree by group, kind, namespace and name
+// Kind matching is case-insensitive, and an empty namespace matches any namespace
+func findResourceNode(nodes []v1alpha1.ResourceNode, group, kind, namespace, name string) *v1alpha1.ResourceNode {
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Group != group || !strings.EqualFold(node.Kind, kind) {
+			continue
+		}
+		if namespace != "" && node.Namespace != namespace {
+			continue
+		}
+		if node.Name != name {
+			continue
+		}
+		return node
+	}
+	return nil
+}
+
+// summarizeEvents filters, deduplicates and sorts events newest first
+// Events for the same object with the same type and reason are merged, summing their counts
+func summarizeEvents(events []corev1.Event, warningOnly bool, since time.Time) []EventInfo {
+	type merged struct {
+		info      EventInfo
+		firstSeen time.Time
+		lastSeen  time.Time
+	}
+
+	byKey := make(map[string]*merged)
+	order := make([]string, 0)
+	for _, event := range events {
+		if warningOnly && event.Type != corev1.EventTypeWarning {
+			continue
+		}
+
+		firstSeen, lastSeen := eventTimes(event)
+		if !since.IsZero() && lastSeen.Before(since) {
+			continue
+		}
+
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+
+		object := fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name)
+		key := object + "|" + event.Type + "|" + event.Reason
+
+		existing, ok := byKey[key]
+		if !ok {
+			byKey[key] = &merged{
+				info: EventInfo{
+					Type:    event.Type,
+					Reason:  event.Reason,
+					Message: event.Message,
+					Count:   count,
+					Object:  object,
+					Source:  event.Source.Component,
+				},
+				firstSeen: firstSeen,
+				lastSeen:  lastSeen,
+			}
+			order = append(order, key)
+			continue
+		}
+
+		existing.info.Count += count
+		if firstSeen.Before(existing.firstSeen) {
+			existing.firstSeen = firstSeen
+		}
+		// Keep the message of the most recent occurrence
+		if lastSeen.After(existing.lastSeen) {
+			existing.lastSeen = lastSeen
+			existing.info.Message = event.Message
+		}
+	}
+
+	mergedEvents := make([]*merged, 0, len(order))
+	for _, key := range order {
+		mergedEvents = append(mergedEvents, byKey[key])
+	}
+	sort.SliceStable(mergedEvents, func(i, j int) bool {
+		return mergedEvents[i].lastSeen.After(mergedEvents[j].lastSeen)
+	})
+
+	result := make([]EventInfo, 0, len(mergedEvents))
+	for _, m := range mergedEvents {
+		m.info.FirstSeen = formatGoTime(m.firstSeen)
+		m.info.LastSeen = formatGoTime(m.lastSeen)
+		result = append(result, m.info)
+	}
+	return result
+}
+
+// eventTimes returns when an event was first and last seen
+// Newer events only set EventTime, so the legacy timestamps fall back to it
+func eventTimes(event corev1.Event) (time.Time, time.Time) {
+	lastSeen := event.LastTimestamp.Time
+	if lastSeen.IsZero() && event.Series != nil {
+		lastSeen = event.Series.LastObservedTime.Time
+	}
+	if lastSeen.IsZero() {
+		lastSeen = event.EventTime.Time
+	}
+	if lastSeen.IsZero() {
+		lastSeen = event.CreationTimestamp.Time
+	}
+
+	firstSeen := event.FirstTimestamp.Time
+	if firstSeen.IsZero() {
+		firstSeen = event.EventTime.Time
+	}
+	if firstSeen.IsZero() {
+		firstSeen = lastSeen
+	}
+
+	return firstSeen, lastSeen
+}
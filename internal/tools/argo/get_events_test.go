@@ -0,0 +1,123 @@
+package argo
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Get Events", func() {
+	var now time.Time
+
+	newEvent := func(eventType, reason, kind, name, message string, count int32, lastSeen time.Time) corev1.Event {
+		return corev1.Event{
+			Type:           eventType,
+			Reason:         reason,
+			Message:        message,
+			Count:          count,
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+			FirstTimestamp: metav1.NewTime(lastSeen.Add(-time.Minute)),
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+	})
+
+	Describe("summarizeEvents", func() {
+		It("should sort events newest first", func() {
+			events := []corev1.Event{
+				newEvent("Normal", "Scheduled", "Pod", "web-1", "scheduled", 1, now.Add(-10*time.Minute)),
+				newEvent("Warning", "BackOff", "Pod", "web-1", "back-off restarting", 3, now.Add(-1*time.Minute)),
+				newEvent("Normal", "Pulled", "Pod", "web-1", "pulled image", 1, now.Add(-5*time.Minute)),
+			}
+
+			result := summarizeEvents(events, false, time.Time{})
+			Expect(result).To(HaveLen(3))
+			Expect(result[0].Reason).To(Equal("BackOff"))
+			Expect(result[1].Reason).To(Equal("Pulled"))
+			Expect(result[2].Reason).To(Equal("Scheduled"))
+		})
+
+		It("should merge duplicate reasons for the same object and sum counts", func() {
+			events := []corev1.Event{
+				newEvent("Warning", "BackOff", "Pod", "web-1", "older message", 2, now.Add(-5*time.Minute)),
+				newEvent("Warning", "BackOff", "Pod", "web-1", "newer message", 3, now.Add(-1*time.Minute)),
+				newEvent("Warning", "BackOff", "Pod", "web-2", "other pod", 1, now.Add(-2*time.Minute)),
+			}
+
+			result := summarizeEvents(events, false, time.Time{})
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Object).To(Equal("Pod/web-1"))
+			Expect(result[0].Count).To(Equal(int32(5)))
+			Expect(result[0].Message).To(Equal("newer message"))
+			Expect(result[1].Object).To(Equal("Pod/web-2"))
+		})
+
+		It("should filter to warnings only", func() {
+			events := []corev1.Event{
+				newEvent("Normal", "Scheduled", "Pod", "web-1", "scheduled", 1, now),
+				newEvent("Warning", "Failed", "Pod", "web-1", "failed", 1, now),
+			}
+
+			result := summarizeEvents(events, true, time.Time{})
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Type).To(Equal("Warning"))
+		})
+
+		It("should filter by time window", func() {
+			events := []corev1.Event{
+				newEvent("Warning", "Old", "Pod", "web-1", "old", 1, now.Add(-2*time.Hour)),
+				newEvent("Warning", "Recent", "Pod", "web-1", "recent", 1, now.Add(-5*time.Minute)),
+			}
+
+			result := summarizeEvents(events, false, now.Add(-30*time.Minute))
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Reason).To(Equal("Recent"))
+		})
+
+		It("should fall back to event time and treat zero count as one", func() {
+			event := corev1.Event{
+				Type:           "Normal",
+				Reason:         "Created",
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1"},
+				EventTime:      metav1.NewMicroTime(now),
+			}
+
+			result := summarizeEvents([]corev1.Event{event}, false, now.Add(-time.Minute))
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Count).To(Equal(int32(1)))
+			Expect(result[0].LastSeen).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("findResourceNode", func() {
+		nodes := []v1alpha1.ResourceNode{
+			{ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web", UID: "uid-1"}},
+			{ResourceRef: v1alpha1.ResourceRef{Kind: "Service", Namespace: "guestbook", Name: "web", UID: "uid-2"}},
+		}
+
+		DescribeTable("resource lookup",
+			func(group, kind, namespace, name, expectedUID string) {
+				node := findResourceNode(nodes, group, kind, namespace, name)
+				if expectedUID == "" {
+					Expect(node).To(BeNil())
+					return
+				}
+				Expect(node).NotTo(BeNil())
+				Expect(node.UID).To(Equal(expectedUID))
+			},
+			Entry("matches group, kind, namespace and name", "apps", "Deployment", "guestbook", "web", "uid-1"),
+			Entry("kind match is case-insensitive", "", "service", "guestbook", "web", "uid-2"),
+			Entry("empty namespace matches any namespace", "apps", "Deployment", "", "web", "uid-1"),
+			Entry("group must match", "", "Deployment", "guestbook", "web", ""),
+			Entry("name must match", "apps", "Deployment", "guestbook", "api", ""),
+		)
+	})
+})
@@ -0,0 +1,149 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetLiveResourceInput defines the input parameters for getting the live manifest of a managed resource
+type GetLiveResourceInput struct {
+	Name                 string `json:"name" jsonschema:"application name"`
+	AppNamespace         string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Group                string `json:"group,omitempty" jsonschema:"API group of the resource (empty for core resources)"`
+	Version              string `json:"version,omitempty" jsonschema:"optional API version of the resource, resolved from the resource tree when empty"`
+	Kind                 string `json:"kind" jsonschema:"kind of the resource"`
+	Namespace            string `json:"namespace,omitempty" jsonschema:"namespace of the resource (empty for cluster-scoped resources)"`
+	ResourceName         string `json:"resourceName" jsonschema:"name of the resource"`
+	Format               string `json:"format,omitempty" jsonschema:"output format: yaml (default) or json"`
+	IncludeManagedFields bool   `json:"includeManagedFields,omitempty" jsonschema:"keep metadata.managedFields, which are stripped by default"`
+}
+
+// GetLiveResourceOutput defines the output structure for getting the live manifest of a managed resource
+type GetLiveResourceOutput struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Manifest  string `json:"manifest" jsonschema:"live manifest of the resource as stored in the cluster"`
+}
+
+// NewGetLiveResourceHandler creates a GetLiveResource handler with the provided AppContext
+func NewGetLiveResourceHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetLiveResourceInput) (*mcp.CallToolResult, GetLiveResourceOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetLiveResourceInput) (*mcp.CallToolResult, GetLiveResourceOutput, error) {
+		l := log.Logger().With("component", "argocd_get_live_resource")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_live_resource completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetLiveResourceOutput{}, fmt.Errorf("application name is required")
+		}
+		if input.Kind == "" || input.ResourceName == "" {
+			return nil, GetLiveResourceOutput{}, fmt.Errorf("kind and resourceName are required")
+		}
+		format, err := manifestFormat(input.Format)
+		if err != nil {
+			return nil, GetLiveResourceOutput{}, err
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetLiveResourceOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query, err := resolveResourceRequest(ctx, appClient, input.Name, input.AppNamespace, input.Group, input.Version, input.Kind, input.Namespace, input.ResourceName)
+		if err != nil {
+			return nil, GetLiveResourceOutput{}, err
+		}
+		kind, namespace, version := query.GetKind(), query.GetNamespace(), query.GetVersion()
+
+		response, err := appClient.GetResource(ctx, query)
+		if err != nil {
+			return nil, GetLiveResourceOutput{}, fmt.Errorf("failed to get resource %s/%s/%s/%s of application %q: %w", input.Group, kind, namespace, input.ResourceName, input.Name, err)
+		}
+		l.Infow("Fetched live resource", "name", input.Name, "kind", kind, "resource", input.ResourceName)
+
+		manifest, err := renderLiveManifest(response.GetManifest(), format, input.IncludeManagedFields)
+		if err != nil {
+			return nil, GetLiveResourceOutput{}, fmt.Errorf("failed to render resource %q: %w", input.ResourceName, err)
+		}
+
+		return nil, GetLiveResourceOutput{
+			Group:     input.Group,
+			Version:   version,
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      input.ResourceName,
+			Manifest:  manifest,
+		}, nil
+	}
+}
+
+// resolveResourceRequest builds a request for a managed resource of an application
+// The API requires a version, so the resource is looked up in the tree when the caller doesn't know it
+func resolveResourceRequest(ctx context.Context, appClient application.ApplicationServiceClient, appName, appNamespace, group, version, kind, namespace, resourceName string) (*application.ApplicationResourceRequest, error) {
+	if version == "" {
+		treeQuery := &application.ResourcesQuery{ApplicationName: &appName}
+		if appNamespace != "" {
+			treeQuery.AppNamespace = &appNamespace
+		}
+		tree, err := appClient.ResourceTree(ctx, treeQuery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource tree for application %q: %w", appName, err)
+		}
+
+		node := findResourceNode(append(tree.Nodes, tree.OrphanedNodes...), group, kind, namespace, resourceName)
+		if node == nil {
+			return nil, fmt.Errorf("resource %s/%s/%s/%s not found in application %q", group, kind, namespace, resourceName, appName)
+		}
+		kind = node.Kind
+		namespace = node.Namespace
+		version = node.Version
+	}
+
+	query := &application.ApplicationResourceRequest{
+		Name:         &appName,
+		Namespace:    &namespace,
+		ResourceName: &resourceName,
+		Version:      &version,
+		Group:        &group,
+		Kind:         &kind,
+	}
+	if appNamespace != "" {
+		query.AppNamespace = &appNamespace
+	}
+	return query, nil
+}
+
+// renderLiveManifest renders a live JSON manifest in the requested format
+// managedFields are stripped unless includeManagedFields is set, as they are large and rarely useful
+func renderLiveManifest(manifest, format string, includeManagedFields bool) (string, error) {
+	if isEmptyState(manifest) {
+		return "", fmt.Errorf("resource has no live manifest")
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(manifest), &obj); err != nil {
+		return "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if !includeManagedFields {
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "managedFields")
+		}
+	}
+
+	return renderManifest(obj, format)
+}
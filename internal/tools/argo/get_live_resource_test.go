@@ -0,0 +1,47 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Get Live Resource", func() {
+	Describe("renderLiveManifest", func() {
+		manifest := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","managedFields":[{"manager":"argocd-controller"}]},"status":{"replicas":2}}`
+
+		It("should strip managedFields by default", func() {
+			result, err := renderLiveManifest(manifest, ManifestFormatYAML, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(ContainSubstring("name: web"))
+			Expect(result).NotTo(ContainSubstring("managedFields"))
+		})
+
+		It("should keep status", func() {
+			result, err := renderLiveManifest(manifest, ManifestFormatYAML, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(ContainSubstring("replicas: 2"))
+		})
+
+		It("should keep managedFields when requested", func() {
+			result, err := renderLiveManifest(manifest, ManifestFormatYAML, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(ContainSubstring("managedFields"))
+		})
+
+		It("should render json", func() {
+			result, err := renderLiveManifest(manifest, ManifestFormatJSON, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(ContainSubstring(`"kind": "Deployment"`))
+		})
+
+		DescribeTable("invalid manifests",
+			func(manifest string) {
+				_, err := renderLiveManifest(manifest, ManifestFormatYAML, false)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("empty", ""),
+			Entry("null", "null"),
+			Entry("not json", "not json"),
+		)
+	})
+})
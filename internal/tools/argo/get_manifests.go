@@ -0,0 +1,192 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ManifestFormatYAML renders manifests as YAML
+	ManifestFormatYAML = "yaml"
+
+	// ManifestFormatJSON renders manifests as indented JSON
+	ManifestFormatJSON = "json"
+)
+
+// GetManifestsInput defines the input parameters for getting the rendered manifests of an application
+type GetManifestsInput struct {
+	Name           string   `json:"name" jsonschema:"application name"`
+	AppNamespace   string   `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Revision       string   `json:"revision,omitempty" jsonschema:"optional revision to render, defaults to the application's target revision"`
+	SourcePosition int64    `json:"sourcePosition,omitempty" jsonschema:"optional 1-based source position the revision applies to, for multi-source applications"`
+	Kinds          []string `json:"kinds,omitempty" jsonschema:"optional list of kinds to include, case-insensitive"`
+	ResourceName   string   `json:"resourceName,omitempty" jsonschema:"optional object name or glob pattern, e.g. web-*"`
+	Format         string   `json:"format,omitempty" jsonschema:"output format of each manifest: yaml (default) or json"`
+	Summary        bool     `json:"summary,omitempty" jsonschema:"list only object identities without manifest bodies, for large applications"`
+}
+
+// GetManifestsOutput defines the output structure for getting rendered manifests
+type GetManifestsOutput struct {
+	Revision   string           `json:"revision,omitempty" jsonschema:"resolved revision the manifests were rendered from"`
+	SourceType string           `json:"sourceType,omitempty" jsonschema:"tool used to render the manifests, e.g. Helm or Kustomize"`
+	Total      int              `json:"total" jsonschema:"number of rendered objects before filtering"`
+	Count      int              `json:"count" jsonschema:"number of objects returned"`
+	Objects    []ManifestObject `json:"objects" jsonschema:"rendered Kubernetes objects"`
+}
+
+// ManifestObject is a single rendered Kubernetes object
+type ManifestObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Manifest   string `json:"manifest,omitempty"`
+}
+
+// NewGetManifestsHandler creates a GetManifests handler with the provided AppContext
+func NewGetManifestsHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetManifestsInput) (*mcp.CallToolResult, GetManifestsOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetManifestsInput) (*mcp.CallToolResult, GetManifestsOutput, error) {
+		l := log.Logger().With("component", "argocd_get_manifests")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_manifests completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetManifestsOutput{}, fmt.Errorf("application name is required")
+		}
+		format, err := manifestFormat(input.Format)
+		if err != nil {
+			return nil, GetManifestsOutput{}, err
+		}
+		if input.SourcePosition != 0 && input.Revision == "" {
+			return nil, GetManifestsOutput{}, fmt.Errorf("sourcePosition requires a revision")
+		}
+		if _, err := path.Match(input.ResourceName, ""); err != nil {
+			return nil, GetManifestsOutput{}, fmt.Errorf("invalid resourceName pattern: %w", err)
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetManifestsOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ApplicationManifestQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+		// Multi-source applications take a revision per source position instead of a single revision
+		if input.SourcePosition != 0 {
+			query.SourcePositions = []int64{input.SourcePosition}
+			query.Revisions = []string{input.Revision}
+		} else if input.Revision != "" {
+			query.Revision = &input.Revision
+		}
+
+		response, err := appClient.GetManifests(ctx, query)
+		if err != nil {
+			return nil, GetManifestsOutput{}, fmt.Errorf("failed to get manifests for application %q: %w", input.Name, err)
+		}
+		l.Infow("Fetched manifests", "name", input.Name, "revision", response.Revision, "count", len(response.Manifests))
+
+		objects, err := filterManifests(response.Manifests, input.Kinds, input.ResourceName, format, input.Summary)
+		if err != nil {
+			return nil, GetManifestsOutput{}, fmt.Errorf("failed to render manifests for application %q: %w", input.Name, err)
+		}
+
+		return nil, GetManifestsOutput{
+			Revision:   response.Revision,
+			SourceType: response.SourceType,
+			Total:      len(response.Manifests),
+			Count:      len(objects),
+			Objects:    objects,
+		}, nil
+	}
+}
+
+// manifestFormat validates an output format, defaulting to YAML
+func manifestFormat(format string) (string, error) {
+	switch format {
+	case "", ManifestFormatYAML:
+		return ManifestFormatYAML, nil
+	case ManifestFormatJSON:
+		return ManifestFormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, expected %q or %q", format, ManifestFormatYAML, ManifestFormatJSON)
+	}
+}
+
+// filterManifests parses JSON manifests, keeps those matching the kind and name filters and renders them
+// In summary mode only object identities are returned
+func filterManifests(manifests []string, kinds []string, namePattern, format string, summary bool) ([]ManifestObject, error) {
+	objects := make([]ManifestObject, 0)
+	for _, manifest := range manifests {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(manifest), &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+
+		object := ManifestObject{
+			APIVersion: stringField(obj, "apiVersion"),
+			Kind:       stringField(obj, "kind"),
+		}
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			object.Namespace = stringField(metadata, "namespace")
+			object.Name = stringField(metadata, "name")
+		}
+
+		if len(kinds) > 0 && !containsFold(kinds, object.Kind) {
+			continue
+		}
+		if namePattern != "" {
+			if matched, _ := path.Match(namePattern, object.Name); !matched {
+				continue
+			}
+		}
+
+		if !summary {
+			rendered, err := renderManifest(obj, format)
+			if err != nil {
+				return nil, err
+			}
+			object.Manifest = rendered
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// renderManifest serializes an object as YAML or indented JSON
+func renderManifest(obj map[string]interface{}, format string) (string, error) {
+	if format == ManifestFormatJSON {
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// stringField returns a string value from an unstructured object, or an empty string
+func stringField(obj map[string]interface{}, key string) string {
+	value, _ := obj[key].(string)
+	return value
+}
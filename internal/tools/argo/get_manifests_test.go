@@ -0,0 +1,91 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Get Manifests", func() {
+	manifests := []string{
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"guestbook"},"spec":{"replicas":2}}`,
+		`{"apiVersion":"v1","kind":"Service","metadata":{"name":"web","namespace":"guestbook"}}`,
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"api-config"}}`,
+	}
+
+	DescribeTable("manifestFormat",
+		func(format, expected string, expectErr bool) {
+			result, err := manifestFormat(format)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("defaults to yaml", "", ManifestFormatYAML, false),
+		Entry("yaml", "yaml", ManifestFormatYAML, false),
+		Entry("json", "json", ManifestFormatJSON, false),
+		Entry("unsupported", "xml", "", true),
+	)
+
+	Describe("filterManifests", func() {
+		It("should return all objects with identities", func() {
+			objects, err := filterManifests(manifests, nil, "", ManifestFormatYAML, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(3))
+			Expect(objects[0].APIVersion).To(Equal("apps/v1"))
+			Expect(objects[0].Kind).To(Equal("Deployment"))
+			Expect(objects[0].Namespace).To(Equal("guestbook"))
+			Expect(objects[0].Name).To(Equal("web"))
+			Expect(objects[2].Namespace).To(BeEmpty())
+		})
+
+		It("should render yaml", func() {
+			objects, err := filterManifests(manifests[:1], nil, "", ManifestFormatYAML, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects[0].Manifest).To(ContainSubstring("kind: Deployment"))
+			Expect(objects[0].Manifest).To(ContainSubstring("replicas: 2"))
+		})
+
+		It("should render json", func() {
+			objects, err := filterManifests(manifests[:1], nil, "", ManifestFormatJSON, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects[0].Manifest).To(ContainSubstring(`"kind": "Deployment"`))
+		})
+
+		It("should omit bodies in summary mode", func() {
+			objects, err := filterManifests(manifests, nil, "", ManifestFormatYAML, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(3))
+			for _, object := range objects {
+				Expect(object.Manifest).To(BeEmpty())
+			}
+		})
+
+		It("should filter by kind case-insensitively", func() {
+			objects, err := filterManifests(manifests, []string{"service", "configmap"}, "", ManifestFormatYAML, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(2))
+			Expect(objects[0].Kind).To(Equal("Service"))
+		})
+
+		It("should filter by name glob", func() {
+			objects, err := filterManifests(manifests, nil, "api-*", ManifestFormatYAML, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(1))
+			Expect(objects[0].Name).To(Equal("api-config"))
+		})
+
+		It("should combine kind and name filters", func() {
+			objects, err := filterManifests(manifests, []string{"Deployment"}, "web", ManifestFormatYAML, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objects).To(HaveLen(1))
+			Expect(objects[0].Kind).To(Equal("Deployment"))
+		})
+
+		It("should return an error for invalid manifests", func() {
+			_, err := filterManifests([]string{"not json"}, nil, "", ManifestFormatYAML, false)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
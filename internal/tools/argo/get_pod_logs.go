@@ -0,0 +1,228 @@
+package argo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// DefaultPodLogTailLines is the number of lines requested per container when no tail or since is given
+	DefaultPodLogTailLines = 100
+
+	// DefaultPodLogMaxBytes is the default cap on returned log content
+	DefaultPodLogMaxBytes = 32 * 1024
+
+	// MaxPodLogMaxBytes is the hard cap on returned log content, regardless of input
+	MaxPodLogMaxBytes = 256 * 1024
+)
+
+// workloadGroups maps workload kinds that own pods to their API group
+var workloadGroups = map[string]string{
+	"Deployment":  "apps",
+	"StatefulSet": "apps",
+	"DaemonSet":   "apps",
+	"ReplicaSet":  "apps",
+	"Job":         "batch",
+	"Rollout":     "argoproj.io",
+}
+
+// GetPodLogsInput defines the input parameters for retrieving pod logs of an application
+type GetPodLogsInput struct {
+	Name          string `json:"name" jsonschema:"application name"`
+	AppNamespace  string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Namespace     string `json:"namespace" jsonschema:"namespace of the pod or workload"`
+	PodName       string `json:"podName,omitempty" jsonschema:"name of a single pod; either podName or resourceKind and resourceName must be set"`
+	ResourceKind  string `json:"resourceKind,omitempty" jsonschema:"workload kind to aggregate logs across all its pods, e.g. Deployment or StatefulSet"`
+	ResourceGroup string `json:"resourceGroup,omitempty" jsonschema:"optional API group of the workload, inferred for common kinds"`
+	ResourceName  string `json:"resourceName,omitempty" jsonschema:"workload name to aggregate logs across all its pods"`
+	Container     string `json:"container,omitempty" jsonschema:"optional container name, defaults to the pod's default container"`
+	TailLines     int64  `json:"tailLines,omitempty" jsonschema:"optional number of lines from the end of each container's log (default 100 when sinceSeconds is unset)"`
+	SinceSeconds  int64  `json:"sinceSeconds,omitempty" jsonschema:"optional relative time in seconds to start returning logs from"`
+	Grep          string `json:"grep,omitempty" jsonschema:"optional regular expression; only matching lines are returned"`
+	Previous      bool   `json:"previous,omitempty" jsonschema:"return logs of the previously terminated container"`
+	MaxBytes      int    `json:"maxBytes,omitempty" jsonschema:"optional cap on returned log bytes (default 32768, max 262144); the newest lines are kept"`
+}
+
+// GetPodLogsOutput defines the output structure for retrieving pod logs
+type GetPodLogsOutput struct {
+	Lines     []LogLine `json:"lines" jsonschema:"log lines in the order they were received"`
+	Bytes     int       `json:"bytes" jsonschema:"total bytes of returned log content"`
+	Truncated bool      `json:"truncated" jsonschema:"true if older lines were dropped to stay within maxBytes"`
+}
+
+// LogLine is a single log line from a pod
+type LogLine struct {
+	Pod       string `json:"pod,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Content   string `json:"content"`
+}
+
+// NewGetPodLogsHandler creates a GetPodLogs handler with the provided AppContext
+func NewGetPodLogsHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetPodLogsInput) (*mcp.CallToolResult, GetPodLogsOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetPodLogsInput) (*mcp.CallToolResult, GetPodLogsOutput, error) {
+		l := log.Logger().With("component", "argocd_get_pod_logs")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_pod_logs completed", "duration", duration)
+		}()
+
+		query, err := buildPodLogsQuery(input)
+		if err != nil {
+			return nil, GetPodLogsOutput{}, err
+		}
+
+		var filter *regexp.Regexp
+		if input.Grep != "" {
+			filter, err = regexp.Compile(input.Grep)
+			if err != nil {
+				return nil, GetPodLogsOutput{}, fmt.Errorf("invalid grep expression: %w", err)
+			}
+		}
+
+		maxBytes := input.MaxBytes
+		if maxBytes <= 0 {
+			maxBytes = DefaultPodLogMaxBytes
+		}
+		maxBytes = min(maxBytes, MaxPodLogMaxBytes)
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetPodLogsOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		// Cancel the stream as soon as we are done reading
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := appClient.PodLogs(streamCtx, query)
+		if err != nil {
+			return nil, GetPodLogsOutput{}, fmt.Errorf("failed to open pod log stream for application %q: %w", input.Name, err)
+		}
+
+		lines, size, truncated, err := collectLogLines(stream.Recv, filter, maxBytes)
+		if err != nil {
+			return nil, GetPodLogsOutput{}, fmt.Errorf("failed to read pod logs for application %q: %w", input.Name, err)
+		}
+		l.Infow("Collected pod logs", "name", input.Name, "lines", len(lines), "bytes", size, "truncated", truncated)
+
+		return nil, GetPodLogsOutput{
+			Lines:     lines,
+			Bytes:     size,
+			Truncated: truncated,
+		}, nil
+	}
+}
+
+// buildPodLogsQuery validates the input and converts it into a PodLogs query
+func buildPodLogsQuery(input GetPodLogsInput) (*application.ApplicationPodLogsQuery, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+	if input.Namespace == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+
+	query := &application.ApplicationPodLogsQuery{
+		Name:      &input.Name,
+		Namespace: &input.Namespace,
+		Previous:  &input.Previous,
+	}
+	if input.AppNamespace != "" {
+		query.AppNamespace = &input.AppNamespace
+	}
+
+	switch {
+	case input.PodName != "" && input.ResourceName != "":
+		return nil, fmt.Errorf("podName and resourceName are mutually exclusive")
+	case input.PodName != "":
+		query.PodName = &input.PodName
+	case input.ResourceKind != "" && input.ResourceName != "":
+		group := input.ResourceGroup
+		if group == "" {
+			group = workloadGroups[input.ResourceKind]
+		}
+		query.Kind = &input.ResourceKind
+		query.Group = &group
+		query.ResourceName = &input.ResourceName
+	default:
+		return nil, fmt.Errorf("either podName or resourceKind and resourceName must be set")
+	}
+
+	if input.Container != "" {
+		query.Container = &input.Container
+	}
+
+	tailLines := input.TailLines
+	if tailLines <= 0 && input.SinceSeconds <= 0 {
+		tailLines = DefaultPodLogTailLines
+	}
+	if tailLines > 0 {
+		query.TailLines = &tailLines
+	}
+	if input.SinceSeconds > 0 {
+		query.SinceSeconds = &input.SinceSeconds
+	}
+
+	return query, nil
+}
+
+// collectLogLines reads log entries until the stream ends, keeping only the newest lines within maxBytes
+// Returns the kept lines, their total size and whether older lines were dropped
+func collectLogLines(recv func() (*application.LogEntry, error), filter *regexp.Regexp, maxBytes int) ([]LogLine, int, bool, error) {
+	lines := make([]LogLine, 0)
+	size := 0
+	truncated := false
+
+	for {
+		entry, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, false, err
+		}
+		if entry.GetLast() {
+			break
+		}
+
+		content := strings.TrimRight(entry.GetContent(), "\n")
+		if filter != nil && !filter.MatchString(content) {
+			continue
+		}
+
+		line := LogLine{
+			Pod:       entry.GetPodName(),
+			Timestamp: entry.GetTimeStampStr(),
+			Content:   content,
+		}
+		lines = append(lines, line)
+		size += logLineSize(line)
+
+		// Drop the oldest lines once over the cap, so the most recent output is kept
+		for size > maxBytes && len(lines) > 0 {
+			size -= logLineSize(lines[0])
+			lines = lines[1:]
+			truncated = true
+		}
+	}
+
+	return lines, size, truncated, nil
+}
+
+// logLineSize approximates the number of bytes a log line contributes to the response
+func logLineSize(line LogLine) int {
+	return len(line.Pod) + len(line.Timestamp) + len(line.Content)
+}
@@ -0,0 +1,140 @@
+package argo
+
+import (
+	"errors"
+	"io"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+)
+
+// fakeLogStream returns a recv function that yields the given entries and then io.EOF
+func fakeLogStream(entries ...*application.LogEntry) func() (*application.LogEntry, error) {
+	i := 0
+	return func() (*application.LogEntry, error) {
+		if i >= len(entries) {
+			return nil, io.EOF
+		}
+		entry := entries[i]
+		i++
+		return entry, nil
+	}
+}
+
+// logEntry builds a log entry for a pod
+func logEntry(pod, content string) *application.LogEntry {
+	return &application.LogEntry{PodName: &pod, Content: &content}
+}
+
+var _ = Describe("Get Pod Logs", func() {
+	Describe("buildPodLogsQuery", func() {
+		It("should require an application name and namespace", func() {
+			_, err := buildPodLogsQuery(GetPodLogsInput{Namespace: "guestbook", PodName: "web-1"})
+			Expect(err).To(MatchError(ContainSubstring("application name is required")))
+
+			_, err = buildPodLogsQuery(GetPodLogsInput{Name: "guestbook", PodName: "web-1"})
+			Expect(err).To(MatchError(ContainSubstring("namespace is required")))
+		})
+
+		It("should require a pod or a workload", func() {
+			_, err := buildPodLogsQuery(GetPodLogsInput{Name: "guestbook", Namespace: "guestbook"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject both a pod and a workload", func() {
+			_, err := buildPodLogsQuery(GetPodLogsInput{
+				Name: "guestbook", Namespace: "guestbook",
+				PodName: "web-1", ResourceKind: "Deployment", ResourceName: "web",
+			})
+			Expect(err).To(MatchError(ContainSubstring("mutually exclusive")))
+		})
+
+		It("should default tail lines when no window is given", func() {
+			query, err := buildPodLogsQuery(GetPodLogsInput{Name: "guestbook", Namespace: "guestbook", PodName: "web-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.GetPodName()).To(Equal("web-1"))
+			Expect(query.GetTailLines()).To(Equal(int64(DefaultPodLogTailLines)))
+			Expect(query.SinceSeconds).To(BeNil())
+		})
+
+		It("should not default tail lines when since is given", func() {
+			query, err := buildPodLogsQuery(GetPodLogsInput{Name: "guestbook", Namespace: "guestbook", PodName: "web-1", SinceSeconds: 300})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.TailLines).To(BeNil())
+			Expect(query.GetSinceSeconds()).To(Equal(int64(300)))
+		})
+
+		DescribeTable("workload group inference",
+			func(kind, group, expectedGroup string) {
+				query, err := buildPodLogsQuery(GetPodLogsInput{
+					Name: "guestbook", Namespace: "guestbook",
+					ResourceKind: kind, ResourceGroup: group, ResourceName: "web",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(query.GetKind()).To(Equal(kind))
+				Expect(query.GetGroup()).To(Equal(expectedGroup))
+				Expect(query.GetResourceName()).To(Equal("web"))
+			},
+			Entry("Deployment", "Deployment", "", "apps"),
+			Entry("StatefulSet", "StatefulSet", "", "apps"),
+			Entry("explicit group wins", "Deployment", "custom.io", "custom.io"),
+			Entry("unknown kind keeps empty group", "Widget", "", ""),
+		)
+	})
+
+	Describe("collectLogLines", func() {
+		It("should collect lines until EOF", func() {
+			recv := fakeLogStream(logEntry("web-1", "hello\n"), logEntry("web-2", "world"))
+
+			lines, size, truncated, err := collectLogLines(recv, nil, DefaultPodLogMaxBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(truncated).To(BeFalse())
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0].Content).To(Equal("hello"))
+			Expect(lines[1].Pod).To(Equal("web-2"))
+			Expect(size).To(Equal(len("web-1hello") + len("web-2world")))
+		})
+
+		It("should stop at the last entry marker", func() {
+			last := true
+			recv := fakeLogStream(logEntry("web-1", "a"), &application.LogEntry{Last: &last}, logEntry("web-1", "b"))
+
+			lines, _, _, err := collectLogLines(recv, nil, DefaultPodLogMaxBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(1))
+		})
+
+		It("should filter lines by regular expression", func() {
+			recv := fakeLogStream(logEntry("web-1", "INFO started"), logEntry("web-1", "ERROR failed"), logEntry("web-1", "error lower"))
+
+			lines, _, _, err := collectLogLines(recv, regexp.MustCompile(`(?i)error`), DefaultPodLogMaxBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0].Content).To(Equal("ERROR failed"))
+		})
+
+		It("should keep the newest lines within the byte cap", func() {
+			recv := fakeLogStream(logEntry("", "0123456789"), logEntry("", "abcdefghij"), logEntry("", "ABCDEFGHIJ"))
+
+			lines, size, truncated, err := collectLogLines(recv, nil, 25)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(truncated).To(BeTrue())
+			Expect(size).To(Equal(20))
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0].Content).To(Equal("abcdefghij"))
+			Expect(lines[1].Content).To(Equal("ABCDEFGHIJ"))
+		})
+
+		It("should return stream errors", func() {
+			recv := func() (*application.LogEntry, error) {
+				return nil, errors.New("stream broken")
+			}
+
+			_, _, _, err := collectLogLines(recv, nil, DefaultPodLogMaxBytes)
+			Expect(err).To(MatchError("stream broken"))
+		})
+	})
+})
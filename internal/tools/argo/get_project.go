@@ -0,0 +1,218 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetProjectInput defines the input parameters for getting a single Argo CD project
+type GetProjectInput struct {
+	Name string `json:"name" jsonschema:"project name"`
+	Live bool   `json:"live,omitempty" jsonschema:"skip the project cache and read live data from Argo CD"`
+}
+
+// GetProjectOutput defines the output structure for getting a single Argo CD project
+type GetProjectOutput struct {
+	Project   ProjectDetail `json:"project" jsonschema:"typed summary of the project"`
+	FromCache bool          `json:"fromCache" jsonschema:"true if the project was served from the project cache"`
+}
+
+// ProjectDetail is a typed summary of an AppProject
+type ProjectDetail struct {
+	Name                       string                `json:"name"`
+	Description                string                `json:"description,omitempty"`
+	SourceRepos                []string              `json:"sourceRepos"`
+	SourceNamespaces           []string              `json:"sourceNamespaces,omitempty"`
+	Destinations               []DestinationInfo     `json:"destinations"`
+	ClusterResourceWhitelist   []GroupKindInfo       `json:"clusterResourceWhitelist,omitempty"`
+	ClusterResourceBlacklist   []GroupKindInfo       `json:"clusterResourceBlacklist,omitempty"`
+	NamespaceResourceWhitelist []GroupKindInfo       `json:"namespaceResourceWhitelist,omitempty"`
+	NamespaceResourceBlacklist []GroupKindInfo       `json:"namespaceResourceBlacklist,omitempty"`
+	Roles                      []ProjectRoleInfo     `json:"roles,omitempty"`
+	SyncWindows                []SyncWindowInfo      `json:"syncWindows,omitempty"`
+	OrphanedResources          *OrphanedResourceInfo `json:"orphanedResources,omitempty"`
+	SignatureKeys              []string              `json:"signatureKeys,omitempty"`
+}
+
+// GroupKindInfo identifies a Kubernetes resource type
+type GroupKindInfo struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+}
+
+// ProjectRoleInfo describes a project role and its policies
+type ProjectRoleInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Policies    []string `json:"policies,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+	Tokens      int      `json:"tokens"`
+}
+
+// SyncWindowInfo describes a window in which syncs are allowed or denied
+type SyncWindowInfo struct {
+	Kind         string   `json:"kind"`
+	Schedule     string   `json:"schedule"`
+	Duration     string   `json:"duration"`
+	TimeZone     string   `json:"timeZone,omitempty"`
+	Applications []string `json:"applications,omitempty"`
+	Namespaces   []string `json:"namespaces,omitempty"`
+	Clusters     []string `json:"clusters,omitempty"`
+	ManualSync   bool     `json:"manualSync"`
+}
+
+// OrphanedResourceInfo describes orphaned resource monitoring settings
+type OrphanedResourceInfo struct {
+	Warn   bool              `json:"warn"`
+	Ignore []OrphanedKeyInfo `json:"ignore,omitempty"`
+}
+
+// OrphanedKeyInfo identifies resources excluded from orphaned resource monitoring
+type OrphanedKeyInfo struct {
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// NewGetProjectHandler creates a GetProject handler with the provided AppContext
+func NewGetProjectHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetProjectInput) (*mcp.CallToolResult, GetProjectOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetProjectInput) (*mcp.CallToolResult, GetProjectOutput, error) {
+		l := log.Logger().With("component", "argocd_get_project")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_project completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetProjectOutput{}, fmt.Errorf("project name is required")
+		}
+
+		// Serve from cache unless live data was requested
+		if !input.Live {
+			if cachedProject := appCtx.FindCachedProject(input.Name); cachedProject != nil {
+				l.Infow("Returning cached project", "name", input.Name)
+				return nil, GetProjectOutput{
+					Project:   summarizeProject(cachedProject),
+					FromCache: true,
+				}, nil
+			}
+		}
+
+		l.Infow("Fetching project from ArgoCD", "name", input.Name, "live", input.Live)
+		proj, err := getProject(ctx, appCtx, input.Name)
+		if err != nil {
+			return nil, GetProjectOutput{}, err
+		}
+
+		return nil, GetProjectOutput{
+			Project:   summarizeProject(proj),
+			FromCache: false,
+		}, nil
+	}
+}
+
+// getProject fetches a single project directly from ArgoCD
+func getProject(ctx context.Context, appCtx *appcontext.AppContext, name string) (*v1alpha1.AppProject, error) {
+	conn, projectClient, err := appCtx.ArgoClient.NewProjectClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project client: %w", err)
+	}
+	defer conn.Close()
+
+	proj, err := projectClient.Get(ctx, &project.ProjectQuery{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %q: %w", name, err)
+	}
+	return proj, nil
+}
+
+// summarizeProject converts an AppProject into a typed summary
+func summarizeProject(proj *v1alpha1.AppProject) ProjectDetail {
+	spec := proj.Spec
+	detail := ProjectDetail{
+		Name:                       proj.Name,
+		Description:                spec.Description,
+		SourceRepos:                make([]string, 0, len(spec.SourceRepos)),
+		SourceNamespaces:           spec.SourceNamespaces,
+		Destinations:               make([]DestinationInfo, 0, len(spec.Destinations)),
+		ClusterResourceWhitelist:   summarizeGroupKinds(spec.ClusterResourceWhitelist),
+		ClusterResourceBlacklist:   summarizeGroupKinds(spec.ClusterResourceBlacklist),
+		NamespaceResourceWhitelist: summarizeGroupKinds(spec.NamespaceResourceWhitelist),
+		NamespaceResourceBlacklist: summarizeGroupKinds(spec.NamespaceResourceBlacklist),
+	}
+	detail.SourceRepos = append(detail.SourceRepos, spec.SourceRepos...)
+
+	for _, dest := range spec.Destinations {
+		detail.Destinations = append(detail.Destinations, DestinationInfo{
+			Server:    dest.Server,
+			Name:      dest.Name,
+			Namespace: dest.Namespace,
+		})
+	}
+
+	for _, role := range spec.Roles {
+		detail.Roles = append(detail.Roles, ProjectRoleInfo{
+			Name:        role.Name,
+			Description: role.Description,
+			Policies:    role.Policies,
+			Groups:      role.Groups,
+			Tokens:      len(role.JWTTokens),
+		})
+	}
+
+	for _, window := range spec.SyncWindows {
+		if window == nil {
+			continue
+		}
+		detail.SyncWindows = append(detail.SyncWindows, SyncWindowInfo{
+			Kind:         window.Kind,
+			Schedule:     window.Schedule,
+			Duration:     window.Duration,
+			TimeZone:     window.TimeZone,
+			Applications: window.Applications,
+			Namespaces:   window.Namespaces,
+			Clusters:     window.Clusters,
+			ManualSync:   window.ManualSync,
+		})
+	}
+
+	if spec.OrphanedResources != nil {
+		orphaned := &OrphanedResourceInfo{Warn: spec.OrphanedResources.IsWarn()}
+		for _, key := range spec.OrphanedResources.Ignore {
+			orphaned.Ignore = append(orphaned.Ignore, OrphanedKeyInfo{
+				Group: key.Group,
+				Kind:  key.Kind,
+				Name:  key.Name,
+			})
+		}
+		detail.OrphanedResources = orphaned
+	}
+
+	for _, key := range spec.SignatureKeys {
+		detail.SignatureKeys = append(detail.SignatureKeys, key.KeyID)
+	}
+
+	return detail
+}
+
+// summarizeGroupKinds converts a list of GroupKinds, returning nil for an empty list
+func summarizeGroupKinds(groupKinds []metav1.GroupKind) []GroupKindInfo {
+	if len(groupKinds) == 0 {
+		return nil
+	}
+	result := make([]GroupKindInfo, 0, len(groupKinds))
+	for _, gk := range groupKinds {
+		result = append(result, GroupKindInfo{Group: gk.Group, Kind: gk.Kind})
+	}
+	return result
+}
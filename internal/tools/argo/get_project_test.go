@@ -0,0 +1,87 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Get Project", func() {
+	Describe("summarizeProject", func() {
+		It("should summarize an empty project with initialized lists", func() {
+			detail := summarizeProject(&v1alpha1.AppProject{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+			Expect(detail.Name).To(Equal("default"))
+			Expect(detail.SourceRepos).NotTo(BeNil())
+			Expect(detail.Destinations).NotTo(BeNil())
+			Expect(detail.ClusterResourceWhitelist).To(BeNil())
+			Expect(detail.OrphanedResources).To(BeNil())
+		})
+
+		It("should summarize a fully configured project", func() {
+			warn := true
+			proj := &v1alpha1.AppProject{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+				Spec: v1alpha1.AppProjectSpec{
+					Description:      "Team A services",
+					SourceRepos:      []string{"https://github.com/example/*"},
+					SourceNamespaces: []string{"team-a-apps"},
+					Destinations: []v1alpha1.ApplicationDestination{
+						{Server: "https://kubernetes.default.svc", Namespace: "team-a-*"},
+					},
+					ClusterResourceWhitelist:   []metav1.GroupKind{{Group: "", Kind: "Namespace"}},
+					NamespaceResourceBlacklist: []metav1.GroupKind{{Group: "", Kind: "ResourceQuota"}},
+					Roles: []v1alpha1.ProjectRole{
+						{
+							Name:      "ci",
+							Policies:  []string{"p, proj:team-a:ci, applications, sync, team-a/*, allow"},
+							JWTTokens: []v1alpha1.JWTToken{{IssuedAt: 1}, {IssuedAt: 2}},
+						},
+					},
+					SyncWindows: v1alpha1.SyncWindows{
+						{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h", Applications: []string{"*"}, ManualSync: true},
+						nil,
+					},
+					OrphanedResources: &v1alpha1.OrphanedResourcesMonitorSettings{
+						Warn:   &warn,
+						Ignore: []v1alpha1.OrphanedResourceKey{{Kind: "ConfigMap", Name: "kube-root-ca.crt"}},
+					},
+					SignatureKeys: []v1alpha1.SignatureKey{{KeyID: "4AEE18F83AFDEB23"}},
+				},
+			}
+
+			detail := summarizeProject(proj)
+			Expect(detail.Description).To(Equal("Team A services"))
+			Expect(detail.SourceRepos).To(ConsistOf("https://github.com/example/*"))
+			Expect(detail.SourceNamespaces).To(ConsistOf("team-a-apps"))
+			Expect(detail.Destinations).To(ConsistOf(DestinationInfo{Server: "https://kubernetes.default.svc", Namespace: "team-a-*"}))
+			Expect(detail.ClusterResourceWhitelist).To(ConsistOf(GroupKindInfo{Kind: "Namespace"}))
+			Expect(detail.NamespaceResourceBlacklist).To(ConsistOf(GroupKindInfo{Kind: "ResourceQuota"}))
+
+			Expect(detail.Roles).To(HaveLen(1))
+			Expect(detail.Roles[0].Name).To(Equal("ci"))
+			Expect(detail.Roles[0].Tokens).To(Equal(2))
+
+			Expect(detail.SyncWindows).To(HaveLen(1))
+			Expect(detail.SyncWindows[0].Kind).To(Equal("deny"))
+			Expect(detail.SyncWindows[0].ManualSync).To(BeTrue())
+
+			Expect(detail.OrphanedResources).NotTo(BeNil())
+			Expect(detail.OrphanedResources.Warn).To(BeTrue())
+			Expect(detail.OrphanedResources.Ignore).To(ConsistOf(OrphanedKeyInfo{Kind: "ConfigMap", Name: "kube-root-ca.crt"}))
+
+			Expect(detail.SignatureKeys).To(ConsistOf("4AEE18F83AFDEB23"))
+		})
+
+		It("should not warn when orphaned resource warnings are unset", func() {
+			proj := &v1alpha1.AppProject{
+				Spec: v1alpha1.AppProjectSpec{OrphanedResources: &v1alpha1.OrphanedResourcesMonitorSettings{}},
+			}
+
+			detail := summarizeProject(proj)
+			Expect(detail.OrphanedResources).NotTo(BeNil())
+			Expect(detail.OrphanedResources.Warn).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,302 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// ResourceTreeViewNested returns resources as a parent/child hierarchy
+	ResourceTreeViewNested = "nested"
+
+	// ResourceTreeViewFlat returns resources as a flat list with parent references
+	ResourceTreeViewFlat = "flat"
+)
+
+// GetResourceTreeInput defines the input parameters for getting an application's resource tree
+type GetResourceTreeInput struct {
+	Name         string   `json:"name" jsonschema:"application name"`
+	AppNamespace string   `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Kinds        []string `json:"kinds,omitempty" jsonschema:"optional kind filter, e.g. Pod or ReplicaSet (case-insensitive)"`
+	Health       []string `json:"health,omitempty" jsonschema:"optional health status filter, e.g. Degraded or Progressing (case-insensitive)"`
+	View         string   `json:"view,omitempty" jsonschema:"nested (default) for a parent/child hierarchy or flat for a list with parent references"`
+}
+
+// GetResourceTreeOutput defines the output structure for getting an application's resource tree
+type GetResourceTreeOutput struct {
+	View  string             `json:"view" jsonschema:"the view that was returned"`
+	Count int                `json:"count" jsonschema:"number of nodes matching the filters"`
+	Nodes []ResourceTreeNode `json:"nodes,omitempty" jsonschema:"flat list of nodes, set for the flat view"`
+	Tree  interface{}        `json:"tree,omitempty" jsonschema:"root nodes with nested children, set for the nested view"`
+}
+
+// ResourceTreeNode is a typed view of a single node in the application resource tree
+type ResourceTreeNode struct {
+	Group     string   `json:"group,omitempty"`
+	Version   string   `json:"version,omitempty"`
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	UID       string   `json:"uid,omitempty"`
+	Health    string   `json:"health,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Images    []string `json:"images,omitempty"`
+	Parents   []string `json:"parents,omitempty"`
+	CreatedAt string   `json:"createdAt,omitempty"`
+}
+
+// NestedResourceTreeNode is a resource tree node together with its children
+type NestedResourceTreeNode struct {
+	ResourceTreeNode
+	Children []*NestedResourceTreeNode `json:"children,omitempty"`
+}
+
+// NewGetResourceTreeHandler creates a GetResourceTree handler with the provided AppContext
+func NewGetResourceTreeHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, GetResourceTreeInput) (*mcp.CallToolResult, GetResourceTreeOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GetResourceTreeInput) (*mcp.CallToolResult, GetResourceTreeOutput, error) {
+		l := log.Logger().With("component", "argocd_get_resource_tree")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("get_resource_tree completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, GetResourceTreeOutput{}, fmt.Errorf("application name is required")
+		}
+
+		view := input.View
+		if view == "" {
+			view = ResourceTreeViewNested
+		}
+		if view != ResourceTreeViewNested && view != ResourceTreeViewFlat {
+			return nil, GetResourceTreeOutput{}, fmt.Errorf("invalid view %q: must be %q or %q", view, ResourceTreeViewNested, ResourceTreeViewFlat)
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, GetResourceTreeOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ResourcesQuery{ApplicationName: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+
+		tree, err := appClient.ResourceTree(ctx, query)
+		if err != nil {
+			return nil, GetResourceTreeOutput{}, fmt.Errorf("failed to get resource tree for application %q: %w", input.Name, err)
+		}
+		l.Infow("Fetched resource tree", "name", input.Name, "nodes", len(tree.Nodes))
+
+		matched := filterResourceNodes(tree.Nodes, input.Kinds, input.Health)
+
+		if view == ResourceTreeViewFlat {
+			nodes := make([]ResourceTreeNode, 0, len(matched))
+			for _, node := range matched {
+				nodes = append(nodes, summarizeResourceNode(node))
+			}
+			return nil, GetResourceTreeOutput{
+				View:  view,
+				Count: len(nodes),
+				Nodes: nodes,
+			}, nil
+		}
+
+		return nil, GetResourceTreeOutput{
+			View:  view,
+			Count: len(matched),
+			Tree:  buildNestedResourceTree(tree.Nodes, matched),
+		}, nil
+	}
+}
+
+// filterResourceNodes returns the nodes matching the optional kind and health filters
+func filterResourceNodes(nodes []v1alpha1.ResourceNode, kinds, healthStatuses []string) []v1alpha1.ResourceNode {
+	if len(kinds) == 0 && len(healthStatuses) == 0 {
+		return nodes
+	}
+
+	filtered := make([]v1alpha1.ResourceNode, 0)
+	for _, node := range nodes {
+		// Check kind filter
+		if len(kinds) > 0 && !containsFold(kinds, node.Kind) {
+			continue
+		}
+
+		// Check health filter
+		if len(healthStatuses) > 0 {
+			if node.Health == nil || !containsFold(healthStatuses, string(node.Health.Status)) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, node)
+	}
+
+	return filtered
+}
+
+// buildNestedResourceTree arranges nodes into a parent/child hierarchy
+// Only matched nodes and their ancestors are included, so filtered results keep their context
+func buildNestedResourceTree(all, matched []v1alpha1.ResourceNode) []*NestedResourceTreeNode {
+	index := newResourceNodeIndex(all)
+
+	// Collect matched nodes plus every ancestor reachable through parent refs
+	included := make(map[string]bool)
+	var include func(ref v1alpha1.ResourceRef)
+	include = func(ref v1alpha1.ResourceRef) {
+		key, ok := index.lookup(ref)
+		if !ok || included[key] {
+			return
+		}
+		included[key] = true
+		for _, parent := range index.nodes[key].ParentRefs {
+			include(parent)
+		}
+	}
+	for _, node := range matched {
+		include(node.ResourceRef)
+	}
+
+	// Build nested nodes in the original order so output is stable
+	nested := make(map[string]*NestedResourceTreeNode, len(included))
+	for _, node := range all {
+		key := formatResourceRef(node.ResourceRef)
+		if included[key] {
+			nested[key] = &NestedResourceTreeNode{ResourceTreeNode: summarizeResourceNode(node)}
+		}
+	}
+
+	// Each node hangs under its first included parent
+	parents := make(map[string]string, len(nested))
+	for _, node := range all {
+		key := formatResourceRef(node.ResourceRef)
+		if _, ok := nested[key]; !ok {
+			continue
+		}
+		for _, parent := range node.ParentRefs {
+			parentKey, ok := index.lookup(parent)
+			if !ok {
+				continue
+			}
+			if _, ok := nested[parentKey]; ok {
+				parents[key] = parentKey
+				break
+			}
+		}
+	}
+
+	// Nodes whose parent chain leads back to themselves, e.g. from a bad ownerRef, become roots instead of disappearing
+	roots := make([]*NestedResourceTreeNode, 0)
+	for _, node := range all {
+		key := formatResourceRef(node.ResourceRef)
+		current, ok := nested[key]
+		if !ok {
+			continue
+		}
+		if parentKey, ok := parents[key]; ok && !inParentCycle(parents, key) {
+			nested[parentKey].Children = append(nested[parentKey].Children, current)
+		} else {
+			roots = append(roots, current)
+		}
+	}
+
+	return roots
+}
+
+// inParentCycle reports whether following the parents of a node leads back to the node itself
+func inParentCycle(parents map[string]string, key string) bool {
+	seen := make(map[string]bool)
+	for current, ok := parents[key]; ok; current, ok = parents[current] {
+		if current == key {
+			return true
+		}
+		if seen[current] {
+			return false
+		}
+		seen[current] = true
+	}
+	return false
+}
+
+// resourceNodeIndex resolves resource references to nodes by UID or by group/kind/namespace/name
+type resourceNodeIndex struct {
+	nodes map[string]v1alpha1.ResourceNode
+	uids  map[string]string
+}
+
+// newResourceNodeIndex indexes the given nodes
+func newResourceNodeIndex(nodes []v1alpha1.ResourceNode) *resourceNodeIndex {
+	index := &resourceNodeIndex{
+		nodes: make(map[string]v1alpha1.ResourceNode, len(nodes)),
+		uids:  make(map[string]string, len(nodes)),
+	}
+	for _, node := range nodes {
+		key := formatResourceRef(node.ResourceRef)
+		index.nodes[key] = node
+		if node.UID != "" {
+			index.uids[node.UID] = key
+		}
+	}
+	return index
+}
+
+// lookup returns the index key for a reference, preferring its UID when set
+func (idx *resourceNodeIndex) lookup(ref v1alpha1.ResourceRef) (string, bool) {
+	if ref.UID != "" {
+		if key, ok := idx.uids[ref.UID]; ok {
+			return key, true
+		}
+	}
+	key := formatResourceRef(ref)
+	_, ok := idx.nodes[key]
+	return key, ok
+}
+
+// summarizeResourceNode converts a raw resource node into its typed view
+func summarizeResourceNode(node v1alpha1.ResourceNode) ResourceTreeNode {
+	summary := ResourceTreeNode{
+		Group:     node.Group,
+		Version:   node.Version,
+		Kind:      node.Kind,
+		Namespace: node.Namespace,
+		Name:      node.Name,
+		UID:       node.UID,
+		Images:    node.Images,
+		CreatedAt: formatTime(node.CreatedAt),
+	}
+	if node.Health != nil {
+		summary.Health = string(node.Health.Status)
+		summary.Message = node.Health.Message
+	}
+	for _, parent := range node.ParentRefs {
+		summary.Parents = append(summary.Parents, formatResourceRef(parent))
+	}
+	return summary
+}
+
+// formatResourceRef renders a resource reference as group/kind/namespace/name
+func formatResourceRef(ref v1alpha1.ResourceRef) string {
+	return strings.Join([]string{ref.Group, ref.Kind, ref.Namespace, ref.Name}, "/")
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("Get Resource Tree", func() {
+	var nodes []v1alpha1.ResourceNode
+
+	BeforeEach(func() {
+		deployment := v1alpha1.ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "guestbook", Name: "web", UID: "uid-deploy"}
+		replicaSet := v1alpha1.ResourceRef{Group: "apps", Version: "v1", Kind: "ReplicaSet", Namespace: "guestbook", Name: "web-abc", UID: "uid-rs"}
+
+		nodes = []v1alpha1.ResourceNode{
+			{
+				ResourceRef: deployment,
+				Health:      &v1alpha1.HealthStatus{Status: "Degraded"},
+			},
+			{
+				ResourceRef: replicaSet,
+				ParentRefs:  []v1alpha1.ResourceRef{deployment},
+				Health:      &v1alpha1.HealthStatus{Status: "Degraded"},
+			},
+			{
+				ResourceRef: v1alpha1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: "guestbook", Name: "web-abc-1", UID: "uid-pod-1"},
+				ParentRefs:  []v1alpha1.ResourceRef{replicaSet},
+				Health:      &v1alpha1.HealthStatus{Status: "Healthy"},
+				Images:      []string{"nginx:1.25"},
+			},
+			{
+				ResourceRef: v1alpha1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: "guestbook", Name: "web-abc-2", UID: "uid-pod-2"},
+				ParentRefs:  []v1alpha1.ResourceRef{replicaSet},
+				Health:      &v1alpha1.HealthStatus{Status: "Degraded", Message: "CrashLoopBackOff"},
+				Images:      []string{"nginx:1.25"},
+			},
+			{
+				ResourceRef: v1alpha1.ResourceRef{Version: "v1", Kind: "Service", Namespace: "guestbook", Name: "web", UID: "uid-svc"},
+			},
+		}
+	})
+
+	Describe("filterResourceNodes", func() {
+		DescribeTable("kind and health filters",
+			func(kinds, healthStatuses []string, expectedNames []string) {
+				result := filterResourceNodes(nodes, kinds, healthStatuses)
+				names := make([]string, 0, len(result))
+				for _, node := range result {
+					names = append(names, node.Name)
+				}
+				Expect(names).To(Equal(expectedNames))
+			},
+			Entry("no filters returns all nodes",
+				nil, nil,
+				[]string{"web", "web-abc", "web-abc-1", "web-abc-2", "web"},
+			),
+			Entry("filter by kind is case-insensitive",
+				[]string{"pod"}, nil,
+				[]string{"web-abc-1", "web-abc-2"},
+			),
+			Entry("filter by health",
+				nil, []string{"Degraded"},
+				[]string{"web", "web-abc", "web-abc-2"},
+			),
+			Entry("combined kind and health filters",
+				[]string{"Pod"}, []string{"degraded"},
+				[]string{"web-abc-2"},
+			),
+			Entry("health filter skips nodes without health",
+				[]string{"Service"}, []string{"Healthy"},
+				[]string{},
+			),
+		)
+	})
+
+	Describe("buildNestedResourceTree", func() {
+		It("should nest children under their parents", func() {
+			roots := buildNestedResourceTree(nodes, nodes)
+
+			Expect(roots).To(HaveLen(2))
+			Expect(roots[0].Kind).To(Equal("Deployment"))
+			Expect(roots[1].Kind).To(Equal("Service"))
+
+			Expect(roots[0].Children).To(HaveLen(1))
+			replicaSet := roots[0].Children[0]
+			Expect(replicaSet.Kind).To(Equal("ReplicaSet"))
+			Expect(replicaSet.Children).To(HaveLen(2))
+			Expect(replicaSet.Children[0].Name).To(Equal("web-abc-1"))
+			Expect(replicaSet.Children[1].Name).To(Equal("web-abc-2"))
+		})
+
+		It("should keep ancestors of matched nodes", func() {
+			matched := filterResourceNodes(nodes, []string{"Pod"}, []string{"Degraded"})
+			roots := buildNestedResourceTree(nodes, matched)
+
+			Expect(roots).To(HaveLen(1))
+			Expect(roots[0].Kind).To(Equal("Deployment"))
+			Expect(roots[0].Children).To(HaveLen(1))
+			Expect(roots[0].Children[0].Children).To(HaveLen(1))
+			Expect(roots[0].Children[0].Children[0].Name).To(Equal("web-abc-2"))
+		})
+
+		It("should resolve parents referenced without a UID", func() {
+			nodes[1].ParentRefs = []v1alpha1.ResourceRef{{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web"}}
+			roots := buildNestedResourceTree(nodes, nodes[1:2])
+
+			Expect(roots).To(HaveLen(1))
+			Expect(roots[0].Kind).To(Equal("Deployment"))
+			Expect(roots[0].Children).To(HaveLen(1))
+		})
+
+		It("should keep nodes that are their own parent as roots", func() {
+			nodes[4].ParentRefs = []v1alpha1.ResourceRef{nodes[4].ResourceRef}
+			roots := buildNestedResourceTree(nodes, nodes)
+
+			Expect(roots).To(HaveLen(2))
+			Expect(roots[1].Kind).To(Equal("Service"))
+			Expect(roots[1].Children).To(BeEmpty())
+		})
+
+		It("should keep nodes whose parents form a loop as roots, with their children", func() {
+			// The Deployment and ReplicaSet are each other's parent, and the pods still hang off the ReplicaSet
+			nodes[0].ParentRefs = []v1alpha1.ResourceRef{nodes[1].ResourceRef}
+			roots := buildNestedResourceTree(nodes, nodes[2:3])
+
+			Expect(roots).To(HaveLen(2))
+			Expect(roots[0].Kind).To(Equal("Deployment"))
+			Expect(roots[0].Children).To(BeEmpty())
+			Expect(roots[1].Kind).To(Equal("ReplicaSet"))
+			Expect(roots[1].Children).To(HaveLen(1))
+			Expect(roots[1].Children[0].Name).To(Equal("web-abc-1"))
+		})
+
+		It("should return an empty tree when nothing matches", func() {
+			roots := buildNestedResourceTree(nodes, []v1alpha1.ResourceNode{})
+			Expect(roots).To(BeEmpty())
+		})
+	})
+
+	Describe("summarizeResourceNode", func() {
+		It("should map health, images and parents", func() {
+			summary := summarizeResourceNode(nodes[3])
+
+			Expect(summary.Kind).To(Equal("Pod"))
+			Expect(summary.Health).To(Equal("Degraded"))
+			Expect(summary.Message).To(Equal("CrashLoopBackOff"))
+			Expect(summary.Images).To(ConsistOf("nginx:1.25"))
+			Expect(summary.Parents).To(ConsistOf("apps/ReplicaSet/guestbook/web-abc"))
+		})
+	})
+})
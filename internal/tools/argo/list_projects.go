@@ -0,0 +1,65 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ListProjectsInput defines the input parameters for listing Argo CD projects
+type ListProjectsInput struct {
+	Live bool `json:"live,omitempty" jsonschema:"skip the project cache and read live data from Argo CD"`
+}
+
+// ListProjectsOutput defines the output structure for listing Argo CD projects
+type ListProjectsOutput struct {
+	Count     int             `json:"count" jsonschema:"number of projects"`
+	Projects  []ProjectDetail `json:"projects" jsonschema:"typed summary of each project"`
+	FromCache bool            `json:"fromCache" jsonschema:"true if the projects were served from the project cache"`
+}
+
+// NewListProjectsHandler creates a ListProjects handler with the provided AppContext
+func NewListProjectsHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, ListProjectsInput) (*mcp.CallToolResult, ListProjectsOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ListProjectsInput) (*mcp.CallToolResult, ListProjectsOutput, error) {
+		l := log.Logger().With("component", "argocd_list_projects")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("list_projects completed", "duration", duration)
+		}()
+
+		// Check if we have cached projects
+		fromCache := true
+		cachedProjects := appCtx.GetCachedProjects()
+		if cachedProjects == nil || input.Live {
+			// Cache miss, expired or live data requested - refresh from ArgoCD
+			l.Infow("Refreshing projects from ArgoCD", "live", input.Live)
+			if err := appCtx.RefreshProjectCache(ctx); err != nil {
+				return nil, ListProjectsOutput{}, fmt.Errorf("failed to refresh project cache: %w", err)
+			}
+
+			cachedProjects = appCtx.GetCachedProjects()
+			if cachedProjects == nil {
+				return nil, ListProjectsOutput{}, fmt.Errorf("project cache is unexpectedly empty after refresh")
+			}
+			fromCache = false
+		}
+
+		projects := make([]ProjectDetail, 0, len(cachedProjects.Items))
+		for i := range cachedProjects.Items {
+			projects = append(projects, summarizeProject(&cachedProjects.Items[i]))
+		}
+		l.Infow("Returning projects", "count", len(projects), "from_cache", fromCache)
+
+		return nil, ListProjectsOutput{
+			Count:     len(projects),
+			Projects:  projects,
+			FromCache: fromCache,
+		}, nil
+	}
+}
@@ -0,0 +1,100 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"template_cli/internal/appcontext"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// projectListArgoClient hands out a project client that lists the given projects and counts the calls
+type projectListArgoClient struct {
+	apiclient.Client
+	project.ProjectServiceClient
+	projects []v1alpha1.AppProject
+	err      error
+	lists    int
+}
+
+func (c *projectListArgoClient) NewProjectClient() (io.Closer, project.ProjectServiceClient, error) {
+	return io.NopCloser(nil), c, nil
+}
+
+func (c *projectListArgoClient) List(context.Context, *project.ProjectQuery, ...grpc.CallOption) (*v1alpha1.AppProjectList, error) {
+	c.lists++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &v1alpha1.AppProjectList{Items: c.projects}, nil
+}
+
+var _ = Describe("List Projects", func() {
+	var (
+		client  *projectListArgoClient
+		handler func(context.Context, *mcp.CallToolRequest, ListProjectsInput) (*mcp.CallToolResult, ListProjectsOutput, error)
+	)
+
+	BeforeEach(func() {
+		client = &projectListArgoClient{projects: []v1alpha1.AppProject{
+			{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+				Spec: v1alpha1.AppProjectSpec{
+					Description:  "Team A services",
+					SourceRepos:  []string{"https://github.com/example/*"},
+					Destinations: []v1alpha1.ApplicationDestination{{Server: "https://kubernetes.default.svc", Namespace: "team-a-*"}},
+				},
+			},
+		}}
+		appCtx := &appcontext.AppContext{ArgoClient: client}
+		DeferCleanup(appCtx.InvalidateProjectCache)
+		handler = NewListProjectsHandler(appCtx)
+	})
+
+	It("should summarize every project", func() {
+		_, output, err := handler(context.Background(), nil, ListProjectsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Count).To(Equal(2))
+		Expect(output.Projects).To(HaveLen(2))
+		Expect(output.Projects[0].Name).To(Equal("default"))
+		Expect(output.Projects[0].SourceRepos).NotTo(BeNil())
+		Expect(output.Projects[1]).To(Equal(summarizeProject(&client.projects[1])))
+		Expect(output.Projects[1].Destinations).To(ConsistOf(DestinationInfo{Server: "https://kubernetes.default.svc", Namespace: "team-a-*"}))
+	})
+
+	It("should serve projects from the cache until live data is requested", func() {
+		_, output, err := handler(context.Background(), nil, ListProjectsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.FromCache).To(BeFalse())
+
+		client.projects = client.projects[:1]
+		_, output, err = handler(context.Background(), nil, ListProjectsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.FromCache).To(BeTrue())
+		Expect(output.Count).To(Equal(2))
+		Expect(client.lists).To(Equal(1))
+
+		_, output, err = handler(context.Background(), nil, ListProjectsInput{Live: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.FromCache).To(BeFalse())
+		Expect(output.Count).To(Equal(1))
+		Expect(client.lists).To(Equal(2))
+	})
+
+	It("should fail when the projects can't be listed", func() {
+		client.err = fmt.Errorf("permission denied")
+		_, _, err := handler(context.Background(), nil, ListProjectsInput{})
+		Expect(err).To(MatchError("failed to refresh project cache: failed to list projects: permission denied"))
+	})
+})
@@ -0,0 +1,79 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ListRepositoriesInput defines the input parameters for listing repositories
+type ListRepositoriesInput struct {
+	Project    string `json:"project,omitempty" jsonschema:"optional project to filter repositories by"`
+	Type       string `json:"type,omitempty" jsonschema:"optional repository type to filter by: git, helm or oci"`
+	FailedOnly bool   `json:"failedOnly,omitempty" jsonschema:"return only repositories whose connection state is not Successful"`
+	Refresh    bool   `json:"refresh,omitempty" jsonschema:"re-check connectivity of every repository instead of returning cached connection states"`
+}
+
+// ListRepositoriesOutput defines the output structure for listing repositories
+type ListRepositoriesOutput struct {
+	Count        int              `json:"count" jsonschema:"number of repositories returned"`
+	Repositories []RepositoryInfo `json:"repositories" jsonschema:"repositories with credentials redacted"`
+}
+
+// NewListRepositoriesHandler creates a ListRepositories handler with the provided AppContext
+func NewListRepositoriesHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, ListRepositoriesInput) (*mcp.CallToolResult, ListRepositoriesOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ListRepositoriesInput) (*mcp.CallToolResult, ListRepositoriesOutput, error) {
+		l := log.Logger().With("component", "argocd_list_repositories")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("list_repositories completed", "duration", duration)
+		}()
+
+		conn, repoClient, err := appCtx.ArgoClient.NewRepoClient()
+		if err != nil {
+			return nil, ListRepositoriesOutput{}, fmt.Errorf("failed to create repository client: %w", err)
+		}
+		defer conn.Close()
+
+		repoList, err := repoClient.ListRepositories(ctx, &repository.RepoQuery{ForceRefresh: input.Refresh})
+		if err != nil {
+			return nil, ListRepositoriesOutput{}, fmt.Errorf("failed to list repositories: %s", redactMessage(err.Error()))
+		}
+		l.Infow("Fetched repositories", "count", len(repoList.Items), "refresh", input.Refresh)
+
+		repos := filterRepositories(repoList.Items, input)
+		return nil, ListRepositoriesOutput{
+			Count:        len(repos),
+			Repositories: repos,
+		}, nil
+	}
+}
+
+// filterRepositories applies the project, type and connection filters and summarizes the matching repositories
+func filterRepositories(repos v1alpha1.Repositories, input ListRepositoriesInput) []RepositoryInfo {
+	result := make([]RepositoryInfo, 0, len(repos))
+	for _, repo := range repos {
+		if repo == nil {
+			continue
+		}
+		if input.Project != "" && repo.Project != input.Project {
+			continue
+		}
+		if input.Type != "" && repositoryType(repo) != input.Type {
+			continue
+		}
+		if input.FailedOnly && repo.ConnectionState.Status == v1alpha1.ConnectionStatusSuccessful {
+			continue
+		}
+		result = append(result, summarizeRepository(repo))
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ListResourceActionsInput defines the input parameters for listing the actions available on a managed resource
+type ListResourceActionsInput struct {
+	Name         string `json:"name" jsonschema:"application name"`
+	AppNamespace string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Group        string `json:"group,omitempty" jsonschema:"API group of the resource (empty for core resources)"`
+	Version      string `json:"version,omitempty" jsonschema:"optional API version of the resource, resolved from the resource tree when empty"`
+	Kind         string `json:"kind" jsonschema:"kind of the resource"`
+	Namespace    string `json:"namespace,omitempty" jsonschema:"namespace of the resource (empty for cluster-scoped resources)"`
+	ResourceName string `json:"resourceName" jsonschema:"name of the resource"`
+}
+
+// ListResourceActionsOutput defines the output structure for listing the actions available on a managed resource
+type ListResourceActionsOutput struct {
+	Group     string               `json:"group,omitempty"`
+	Version   string               `json:"version"`
+	Kind      string               `json:"kind"`
+	Namespace string               `json:"namespace,omitempty"`
+	Name      string               `json:"name"`
+	Actions   []ResourceActionInfo `json:"actions" jsonschema:"actions defined for the resource, e.g. restart for a Deployment"`
+}
+
+// ResourceActionInfo describes a resource action
+type ResourceActionInfo struct {
+	Name        string                    `json:"name"`
+	DisplayName string                    `json:"displayName,omitempty"`
+	Disabled    bool                      `json:"disabled" jsonschema:"true if the action can't currently run, e.g. resume on a workload that isn't paused"`
+	Params      []ResourceActionParamInfo `json:"params,omitempty"`
+}
+
+// ResourceActionParamInfo describes a parameter of a resource action
+type ResourceActionParamInfo struct {
+	Name    string `json:"name"`
+	Type    string `json:"type,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Default string `json:"default,omitempty"`
+}
+
+// NewListResourceActionsHandler creates a ListResourceActions handler with the provided AppContext
+func NewListResourceActionsHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, ListResourceActionsInput) (*mcp.CallToolResult, ListResourceActionsOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ListResourceActionsInput) (*mcp.CallToolResult, ListResourceActionsOutput, error) {
+		l := log.Logger().With("component", "argocd_list_resource_actions")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("list_resource_actions completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, ListResourceActionsOutput{}, fmt.Errorf("application name is required")
+		}
+		if input.Kind == "" || input.ResourceName == "" {
+			return nil, ListResourceActionsOutput{}, fmt.Errorf("kind and resourceName are required")
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, ListResourceActionsOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query, err := resolveResourceRequest(ctx, appClient, input.Name, input.AppNamespace, input.Group, input.Version, input.Kind, input.Namespace, input.ResourceName)
+		if err != nil {
+			return nil, ListResourceActionsOutput{}, err
+		}
+
+		response, err := appClient.ListResourceActions(ctx, query)
+		if err != nil {
+			return nil, ListResourceActionsOutput{}, fmt.Errorf("failed to list actions of resource %s/%s/%s/%s in application %q: %w", input.Group, query.GetKind(), query.GetNamespace(), input.ResourceName, input.Name, err)
+		}
+		l.Infow("Fetched resource actions", "name", input.Name, "kind", query.GetKind(), "resource", input.ResourceName, "count", len(response.Actions))
+
+		return nil, ListResourceActionsOutput{
+			Group:     input.Group,
+			Version:   query.GetVersion(),
+			Kind:      query.GetKind(),
+			Namespace: query.GetNamespace(),
+			Name:      input.ResourceName,
+			Actions:   summarizeResourceActions(response.Actions),
+		}, nil
+	}
+}
+
+// summarizeResourceActions converts the actions returned by Argo CD
+func summarizeResourceActions(actions []*v1alpha1.ResourceAction) []ResourceActionInfo {
+	result := make([]ResourceActionInfo, 0, len(actions))
+	for _, action := range actions {
+		if action == nil {
+			continue
+		}
+		info := ResourceActionInfo{
+			Name:        action.Name,
+			DisplayName: action.DisplayName,
+			Disabled:    action.Disabled,
+		}
+		for _, param := range action.Params {
+			info.Params = append(info.Params, ResourceActionParamInfo{
+				Name:    param.Name,
+				Type:    param.Type,
+				Value:   param.Value,
+				Default: param.Default,
+			})
+		}
+		result = append(result, info)
+	}
+	return result
+}
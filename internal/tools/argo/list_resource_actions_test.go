@@ -0,0 +1,31 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("List Resource Actions", func() {
+	Describe("summarizeResourceActions", func() {
+		It("should convert actions and their params", func() {
+			actions := []*v1alpha1.ResourceAction{
+				{Name: "restart", DisplayName: "Restart"},
+				nil,
+				{Name: "resume", Disabled: true, Params: []v1alpha1.ResourceActionParam{{Name: "replicas", Type: "int", Default: "1"}}},
+			}
+
+			Expect(summarizeResourceActions(actions)).To(Equal([]ResourceActionInfo{
+				{Name: "restart", DisplayName: "Restart"},
+				{Name: "resume", Disabled: true, Params: []ResourceActionParamInfo{{Name: "replicas", Type: "int", Default: "1"}}},
+			}))
+		})
+
+		It("should return an empty list when there are no actions", func() {
+			result := summarizeResourceActions(nil)
+			Expect(result).NotTo(BeNil())
+			Expect(result).To(BeEmpty())
+		})
+	})
+})
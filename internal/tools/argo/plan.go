@@ -0,0 +1,554 @@
+package argo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sethvargo/go-envconfig"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// PlanChangeToolName is the tool that plans a call to a write tool
+	PlanChangeToolName = "argocd_plan_change"
+
+	// ApplyChangeToolName is the tool that carries out a planned call
+	ApplyChangeToolName = "argocd_apply_change"
+
+	// PlanTTL is how long a plan can be applied after it was made
+	PlanTTL = 15 * time.Minute
+
+	// PlanDryRunTimeout is how long planning waits for a dry-run sync to finish
+	PlanDryRunTimeout = 30 * time.Second
+)
+
+// plannedTools maps each write tool to the argument that previews it without changing anything, if it has one
+var plannedTools = map[string]string{
+	"argocd_sync_application":     "dryRun",
+	"argocd_rollback_application": "dryRun",
+	"argocd_update_application":   "dryRun",
+	"argocd_delete_application":   "dryRun",
+	"argocd_create_application":   "validateOnly",
+	"argocd_refresh_application":  "",
+	"argocd_terminate_operation":  "",
+	"argocd_run_resource_action":  "",
+	"argocd_delete_resource":      "",
+}
+
+// PlanConfig defines whether write tools may only be called through a plan
+type PlanConfig struct {
+	Require bool `env:"MCP_REQUIRE_PLAN,default=true"`
+}
+
+// NewPlanConfigFromEnv loads the plan configuration from environment variables
+func NewPlanConfigFromEnv(ctx context.Context) (*PlanConfig, error) {
+	var cfg PlanConfig
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to process environment variables: %w", err)
+	}
+	return &cfg, nil
+}
+
+// PlanChangeInput defines the input parameters for planning a call to a write tool
+type PlanChangeInput struct {
+	Tool      string         `json:"tool" jsonschema:"write tool to plan, e.g. argocd_sync_application"`
+	Arguments map[string]any `json:"arguments" jsonschema:"arguments for the write tool, as it would be called directly"`
+}
+
+// ChangePlan describes what a planned call will change and the application state it was computed from
+type ChangePlan struct {
+	PlanID          string                   `json:"planId" jsonschema:"opaque ID to pass to argocd_apply_change"`
+	Tool            string                   `json:"tool"`
+	Application     string                   `json:"application"`
+	StateHash       string                   `json:"stateHash" jsonschema:"hash of the application state the plan was computed from; apply refuses to run if it changed"`
+	ExpiresAt       string                   `json:"expiresAt"`
+	SpecDiff        string                   `json:"specDiff,omitempty" jsonschema:"unified diff of the Application manifest, for changes to the application itself"`
+	ResourceChanges []ResourceDiffInfo       `json:"resourceChanges" jsonschema:"managed resources whose live state differs from the target state, as a sync would change them"`
+	DryRunResults   []SyncResourceResultInfo `json:"dryRunResults,omitempty" jsonschema:"per-resource results of a dry-run sync"`
+	Preview         any                      `json:"preview,omitempty" jsonschema:"output of the tool's own dry run, if it has one"`
+	Notes           []string                 `json:"notes,omitempty"`
+}
+
+// ApplyChangeInput defines the input parameters for applying a plan
+type ApplyChangeInput struct {
+	PlanID  string `json:"planId" jsonschema:"plan ID returned by argocd_plan_change"`
+	Confirm string `json:"confirm,omitempty" jsonschema:"one-time confirmation code for clients that can't ask the user directly; only pass a code the user gave you"`
+}
+
+// ApplyChangeOutput defines the output structure for applying a plan
+type ApplyChangeOutput struct {
+	PlanID      string `json:"planId"`
+	Tool        string `json:"tool"`
+	Application string `json:"application"`
+	Result      any    `json:"result" jsonschema:"output of the write tool"`
+}
+
+// planState is a snapshot of an application taken when planning and again when applying
+type planState struct {
+	app     *v1alpha1.Application
+	managed []*v1alpha1.ResourceDiff
+	hash    string
+}
+
+// storedPlan is a plan waiting to be applied
+type storedPlan struct {
+	tool         string
+	arguments    map[string]any
+	name         string
+	appNamespace string
+	stateHash    string
+	expiresAt    time.Time
+}
+
+// Planner lets write tools run in two phases: a plan of what will change, then an apply that only runs if nothing changed since
+type Planner struct {
+	requirePlan bool
+	state       func(ctx context.Context, name, appNamespace string) (*planState, error)
+	watch       func(ctx context.Context, name, appNamespace string) (*v1alpha1.Application, error)
+	now         func() time.Time
+
+	mu       sync.Mutex
+	plans    map[string]*storedPlan
+	dispatch mcp.MethodHandler
+}
+
+// NewPlanner creates a Planner reading application state through the provided AppContext
+// If requirePlan is set, write tools can only be called through a plan, apart from their dry runs
+func NewPlanner(appCtx *appcontext.AppContext, requirePlan bool) *Planner {
+	return &Planner{
+		requirePlan: requirePlan,
+		state: func(ctx context.Context, name, appNamespace string) (*planState, error) {
+			return loadPlanState(ctx, appCtx, name, appNamespace)
+		},
+		watch: func(ctx context.Context, name, appNamespace string) (*v1alpha1.Application, error) {
+			return waitForOperation(ctx, appCtx, name, appNamespace)
+		},
+		now:   time.Now,
+		plans: make(map[string]*storedPlan),
+	}
+}
+
+// Middleware returns MCP middleware that dispatches planned calls and refuses unplanned ones if plans are required
+// It must be added after all other middleware, so that planned calls still pass through them
+func (p *Planner) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		p.mu.Lock()
+		p.dispatch = next
+		p.mu.Unlock()
+
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !p.requirePlan || method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+			tool := callReq.Params.Name
+			if _, planned := plannedTools[tool]; !planned || isPreview(tool, callReq.Params.Arguments) {
+				return next(ctx, method, req)
+			}
+
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s must be planned first: call %s with this tool and its arguments, show the plan to the user, then call %s with the plan ID", tool, PlanChangeToolName, ApplyChangeToolName)}},
+			}, nil
+		}
+	}
+}
+
+// isPreview reports whether a call only previews a change, which needs no plan
+func isPreview(tool string, rawArgs json.RawMessage) bool {
+	flag := plannedTools[tool]
+	if flag == "" || len(rawArgs) == 0 {
+		return false
+	}
+	var args map[string]any
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return false
+	}
+	return args[flag] == true
+}
+
+// NewPlanChangeHandler creates a PlanChange handler with the provided Planner
+func NewPlanChangeHandler(p *Planner) func(context.Context, *mcp.CallToolRequest, PlanChangeInput) (*mcp.CallToolResult, ChangePlan, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input PlanChangeInput) (*mcp.CallToolResult, ChangePlan, error) {
+		l := log.Logger().With("component", "argocd_plan_change")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("plan_change completed", "duration", duration)
+		}()
+
+		previewFlag, ok := plannedTools[input.Tool]
+		if !ok {
+			return nil, ChangePlan{}, fmt.Errorf("%q is not a write tool that can be planned", input.Tool)
+		}
+		if input.Arguments == nil {
+			input.Arguments = make(map[string]any)
+		}
+		if previewFlag != "" && input.Arguments[previewFlag] == true {
+			return nil, ChangePlan{}, fmt.Errorf("arguments already set %s; plan the change itself, the plan includes the preview", previewFlag)
+		}
+		name, _ := input.Arguments["name"].(string)
+		appNamespace, _ := input.Arguments["appNamespace"].(string)
+		if name == "" {
+			return nil, ChangePlan{}, fmt.Errorf("application name is required")
+		}
+
+		state, err := p.state(ctx, name, appNamespace)
+		if err != nil {
+			return nil, ChangePlan{}, err
+		}
+		if state.app == nil && input.Tool != "argocd_create_application" {
+			return nil, ChangePlan{}, fmt.Errorf("application %q not found", name)
+		}
+
+		plan := ChangePlan{
+			Tool:            input.Tool,
+			Application:     name,
+			StateHash:       state.hash,
+			ResourceChanges: make([]ResourceDiffInfo, 0),
+		}
+		for _, item := range state.managed {
+			info, err := diffManagedResource(item, DefaultDiffContextLines)
+			if err != nil {
+				return nil, ChangePlan{}, err
+			}
+			if info.Status != ResourceDiffStatusSynced {
+				plan.ResourceChanges = append(plan.ResourceChanges, info)
+			}
+		}
+
+		if previewFlag == "" {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s has no dry run; applying runs it with the planned arguments against the state above", input.Tool))
+		} else if err := p.preview(ctx, req, input.Tool, previewFlag, input.Arguments, state, &plan); err != nil {
+			return nil, ChangePlan{}, err
+		}
+
+		plan.PlanID = newPlanID()
+		expiresAt := p.now().Add(PlanTTL)
+		plan.ExpiresAt = formatGoTime(expiresAt)
+		p.mu.Lock()
+		p.expirePlans()
+		p.plans[plan.PlanID] = &storedPlan{
+			tool:         input.Tool,
+			arguments:    input.Arguments,
+			name:         name,
+			appNamespace: appNamespace,
+			stateHash:    state.hash,
+			expiresAt:    expiresAt,
+		}
+		p.mu.Unlock()
+		l.Infow("Planned change", "tool", input.Tool, "name", name, "plan_id", plan.PlanID, "resource_changes", len(plan.ResourceChanges))
+
+		return nil, plan, nil
+	}
+}
+
+// preview runs the tool's own dry run and adds its results to the plan
+func (p *Planner) preview(ctx context.Context, req *mcp.CallToolRequest, tool, previewFlag string, arguments map[string]any, state *planState, plan *ChangePlan) error {
+	previewArgs := make(map[string]any, len(arguments)+1)
+	for key, value := range arguments {
+		previewArgs[key] = value
+	}
+	previewArgs[previewFlag] = true
+
+	output, err := p.call(ctx, req, tool, previewArgs)
+	if err != nil {
+		return fmt.Errorf("dry run of %s failed: %w", tool, err)
+	}
+	plan.Preview = output
+
+	// Changes to the application itself come back as a diff or as the manifest that will be created
+	var spec struct {
+		Diff     string `json:"diff"`
+		Manifest string `json:"manifest"`
+	}
+	if data, err := json.Marshal(output); err == nil {
+		_ = json.Unmarshal(data, &spec)
+	}
+	switch {
+	case spec.Diff != "":
+		plan.SpecDiff = spec.Diff
+	case spec.Manifest != "":
+		current := ""
+		if state.app != nil {
+			if current, err = renderApplicationManifest(state.app); err != nil {
+				return fmt.Errorf("failed to render application %q: %w", state.app.Name, err)
+			}
+		}
+		plan.SpecDiff = unifiedDiff("current", "planned", current, spec.Manifest, DefaultDiffContextLines)
+	}
+
+	// Sync and rollback dry runs are operations the controller carries out, so wait for their results
+	if tool == "argocd_sync_application" || tool == "argocd_rollback_application" {
+		watchCtx, cancel := context.WithTimeout(ctx, PlanDryRunTimeout)
+		defer cancel()
+		app, err := p.watch(watchCtx, plan.Application, state.app.Namespace)
+		if err != nil || app == nil || operationInProgress(app) {
+			plan.Notes = append(plan.Notes, "the dry-run sync did not finish in time; its results are missing from the plan")
+			return nil
+		}
+		plan.DryRunResults = summarizeSyncResults(app.Status.OperationState)
+	}
+	return nil
+}
+
+// NewApplyChangeHandler creates an ApplyChange handler with the provided Planner
+func NewApplyChangeHandler(p *Planner) func(context.Context, *mcp.CallToolRequest, ApplyChangeInput) (*mcp.CallToolResult, ApplyChangeOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ApplyChangeInput) (*mcp.CallToolResult, ApplyChangeOutput, error) {
+		l := log.Logger().With("component", "argocd_apply_change")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("apply_change completed", "duration", duration)
+		}()
+
+		if input.PlanID == "" {
+			return nil, ApplyChangeOutput{}, fmt.Errorf("plan ID is required")
+		}
+
+		// The plan is claimed before anything else, so concurrent applies of the same plan can't both dispatch it
+		plan, ok := p.claimPlan(input.PlanID)
+		if !ok {
+			return nil, ApplyChangeOutput{}, fmt.Errorf("plan %q not found; it may have expired or already been applied, call %s again", input.PlanID, PlanChangeToolName)
+		}
+
+		state, err := p.state(ctx, plan.name, plan.appNamespace)
+		if err != nil {
+			return nil, ApplyChangeOutput{}, err
+		}
+		if state.hash != plan.stateHash {
+			return nil, ApplyChangeOutput{}, fmt.Errorf("application %q changed since plan %s was made, so it no longer describes what would happen; call %s again and review the new plan", plan.name, input.PlanID, PlanChangeToolName)
+		}
+
+		args := plan.arguments
+		if input.Confirm != "" {
+			args = make(map[string]any, len(plan.arguments)+1)
+			for key, value := range plan.arguments {
+				args[key] = value
+			}
+			args["confirm"] = input.Confirm
+		}
+
+		l.Infow("Applying plan", "tool", plan.tool, "name", plan.name, "plan_id", input.PlanID)
+		callCtx, pending := trackPendingConfirmation(ctx)
+		output, err := p.call(callCtx, req, plan.tool, args)
+		if err != nil {
+			// Only a call that stopped to wait for the user's confirmation is known not to have run, so only then can it be retried
+			if pending.Load() {
+				p.restorePlan(input.PlanID, plan)
+			}
+			return nil, ApplyChangeOutput{}, err
+		}
+
+		return nil, ApplyChangeOutput{
+			PlanID:      input.PlanID,
+			Tool:        plan.tool,
+			Application: plan.name,
+			Result:      output,
+		}, nil
+	}
+}
+
+// call runs a tool through the middleware below the planner and returns its structured output
+func (p *Planner) call(ctx context.Context, req *mcp.CallToolRequest, tool string, args map[string]any) (any, error) {
+	p.mu.Lock()
+	dispatch := p.dispatch
+	p.mu.Unlock()
+	if dispatch == nil {
+		return nil, fmt.Errorf("the planner middleware is not installed")
+	}
+
+	rawArgs, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments of %s: %w", tool, err)
+	}
+	callReq := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: tool, Arguments: rawArgs}}
+	if req != nil {
+		callReq.Session = req.Session
+		callReq.Extra = req.Extra
+	}
+
+	result, err := dispatch(ctx, "tools/call", callReq)
+	if err != nil {
+		return nil, err
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	if !ok || toolResult == nil {
+		return nil, fmt.Errorf("%s returned no result", tool)
+	}
+	if toolResult.IsError {
+		var messages []string
+		for _, content := range toolResult.Content {
+			if text, ok := content.(*mcp.TextContent); ok {
+				messages = append(messages, text.Text)
+			}
+		}
+		return nil, fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+
+	var output any
+	if toolResult.StructuredContent != nil {
+		data, err := json.Marshal(toolResult.StructuredContent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode output of %s: %w", tool, err)
+		}
+		if err := json.Unmarshal(data, &output); err != nil {
+			return nil, fmt.Errorf("failed to decode output of %s: %w", tool, err)
+		}
+	}
+	return output, nil
+}
+
+// claimPlan removes a plan that hasn't expired and returns it, so only one caller can apply it
+func (p *Planner) claimPlan(id string) (*storedPlan, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expirePlans()
+	plan, ok := p.plans[id]
+	delete(p.plans, id)
+	return plan, ok
+}
+
+// restorePlan puts back a claimed plan that wasn't applied, so it can be applied again
+func (p *Planner) restorePlan(id string, plan *storedPlan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.plans[id] = plan
+}
+
+// expirePlans removes plans past their expiry; the caller must hold the lock
+func (p *Planner) expirePlans() {
+	now := p.now()
+	for id, plan := range p.plans {
+		if now.After(plan.expiresAt) {
+			delete(p.plans, id)
+		}
+	}
+}
+
+// loadPlanState reads the live application and its managed resources and hashes them
+// An application that doesn't exist yet, e.g. when planning its creation, has a state of its own
+func loadPlanState(ctx context.Context, appCtx *appcontext.AppContext, name, appNamespace string) (*planState, error) {
+	conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application client: %w", err)
+	}
+	defer conn.Close()
+
+	query := &application.ApplicationQuery{Name: &name}
+	if appNamespace != "" {
+		query.AppNamespace = &appNamespace
+	}
+	app, err := appClient.Get(ctx, query)
+	if status.Code(err) == codes.NotFound {
+		return &planState{hash: applicationStateHash(nil, nil)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application %q: %w", name, err)
+	}
+
+	managed, err := appClient.ManagedResources(ctx, &application.ResourcesQuery{ApplicationName: &app.Name, AppNamespace: &app.Namespace})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed resources for application %q: %w", name, err)
+	}
+	return &planState{app: app, managed: managed.Items, hash: applicationStateHash(app, managed.Items)}, nil
+}
+
+// applicationStateHash hashes what a change is planned against: the spec, the synced revisions and the live and target state of each managed resource
+// Status that changes on its own, like health, operation state and server-populated metadata, is left out
+func applicationStateHash(app *v1alpha1.Application, managed []*v1alpha1.ResourceDiff) string {
+	type resourceState struct {
+		Key    string `json:"key"`
+		Live   string `json:"live"`
+		Target string `json:"target"`
+	}
+	state := struct {
+		Exists    bool                      `json:"exists"`
+		Spec      *v1alpha1.ApplicationSpec `json:"spec,omitempty"`
+		Revision  string                    `json:"revision,omitempty"`
+		Revisions []string                  `json:"revisions,omitempty"`
+		History   int64                     `json:"history,omitempty"`
+		Resources []resourceState           `json:"resources,omitempty"`
+	}{}
+
+	if app != nil {
+		state.Exists = true
+		state.Spec = &app.Spec
+		state.Revision = app.Status.Sync.Revision
+		state.Revisions = app.Status.Sync.Revisions
+		if len(app.Status.History) > 0 {
+			state.History = app.Status.History.LastRevisionHistory().ID
+		}
+	}
+	for _, item := range managed {
+		if item == nil {
+			continue
+		}
+		// Normalizing can only fail on malformed JSON, which is then hashed as is
+		live, err := normalizeState(item.LiveState)
+		if err != nil {
+			live = item.LiveState
+		}
+		target, err := normalizeState(item.TargetState)
+		if err != nil {
+			target = item.TargetState
+		}
+		state.Resources = append(state.Resources, resourceState{
+			Key:    resourceLabel(item.Group, item.Kind, item.Namespace, item.Name),
+			Live:   live,
+			Target: target,
+		})
+	}
+	sort.Slice(state.Resources, func(i, j int) bool { return state.Resources[i].Key < state.Resources[j].Key })
+
+	data, _ := json.Marshal(state)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// waitForOperation watches an application until its operation has finished or the context ends
+func waitForOperation(ctx context.Context, appCtx *appcontext.AppContext, name, appNamespace string) (*v1alpha1.Application, error) {
+	conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application client: %w", err)
+	}
+	defer conn.Close()
+
+	query := &application.ApplicationQuery{Name: &name}
+	if appNamespace != "" {
+		query.AppNamespace = &appNamespace
+	}
+	stream, err := appClient.Watch(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch application %q: %w", name, err)
+	}
+	app, _, err := watchApplication(stream.Recv, func(app *v1alpha1.Application) bool {
+		return !operationInProgress(app)
+	})
+	if err != nil && ctx.Err() == nil {
+		return app, err
+	}
+	return app, nil
+}
+
+// newPlanID generates an opaque plan ID
+func newPlanID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,283 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// planTestInput and planTestOutput stand in for the input and output of a write tool
+type planTestInput struct {
+	Name    string `json:"name"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+	Confirm string `json:"confirm,omitempty"`
+}
+
+type planTestOutput struct {
+	Name   string `json:"name"`
+	DryRun bool   `json:"dryRun"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+var _ = Describe("Planner", func() {
+	var (
+		planner  *Planner
+		hash     string
+		now      time.Time
+		calls    []planTestInput
+		callsMu  sync.Mutex
+		failing  error
+		applying chan struct{}
+		release  chan struct{}
+		session  *mcp.ClientSession
+	)
+
+	BeforeEach(func() {
+		hash = "state-1"
+		now = time.Unix(1700000000, 0)
+		calls = nil
+		failing = nil
+		applying = nil
+		release = nil
+		app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"}}
+		planner = &Planner{
+			requirePlan: true,
+			state: func(_ context.Context, name, _ string) (*planState, error) {
+				return &planState{app: app, hash: hash}, nil
+			},
+			watch: func(context.Context, string, string) (*v1alpha1.Application, error) {
+				done := app.DeepCopy()
+				done.Status.OperationState = &v1alpha1.OperationState{
+					Phase: common.OperationSucceeded,
+					SyncResult: &v1alpha1.SyncOperationResult{Resources: v1alpha1.ResourceResults{
+						{Kind: "Deployment", Namespace: "web", Name: "guestbook", Status: common.ResultCodeSynced, Message: "configured (dry run)"},
+					}},
+				}
+				return done, nil
+			},
+			now:   func() time.Time { return now },
+			plans: make(map[string]*storedPlan),
+		}
+
+		server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+		fakeTool := func(ctx context.Context, _ *mcp.CallToolRequest, input planTestInput) (*mcp.CallToolResult, planTestOutput, error) {
+			callsMu.Lock()
+			calls = append(calls, input)
+			entered := applying
+			if !input.DryRun {
+				applying = nil
+			}
+			callsMu.Unlock()
+			// The first call that isn't a dry run waits to be released, if set up to
+			if entered != nil && !input.DryRun {
+				close(entered)
+				<-release
+			}
+			if failing != nil {
+				notePendingConfirmation(ctx, failing)
+				return nil, planTestOutput{}, failing
+			}
+			return nil, planTestOutput{Name: input.Name, DryRun: input.DryRun, Diff: "-replicas: 1\n+replicas: 2\n"}, nil
+		}
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_update_application"}, fakeTool)
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_sync_application"}, fakeTool)
+		mcp.AddTool(server, &mcp.Tool{Name: "argocd_refresh_application"}, fakeTool)
+		mcp.AddTool(server, &mcp.Tool{Name: PlanChangeToolName}, NewPlanChangeHandler(planner))
+		mcp.AddTool(server, &mcp.Tool{Name: ApplyChangeToolName}, NewApplyChangeHandler(planner))
+		server.AddReceivingMiddleware(planner.Middleware())
+
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(serverSession.Close)
+
+		client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+		session, err = client.Connect(context.Background(), clientTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(session.Close)
+	})
+
+	call := func(tool string, args map[string]any) *mcp.CallToolResult {
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: tool, Arguments: args})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	errorText := func(result *mcp.CallToolResult) string {
+		Expect(result.IsError).To(BeTrue())
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	plan := func(tool string, args map[string]any) ChangePlan {
+		result := call(PlanChangeToolName, map[string]any{"tool": tool, "arguments": args})
+		Expect(result.IsError).To(BeFalse(), fmt.Sprintf("%v", result.Content))
+		data, err := json.Marshal(result.StructuredContent)
+		Expect(err).NotTo(HaveOccurred())
+		var changePlan ChangePlan
+		Expect(json.Unmarshal(data, &changePlan)).To(Succeed())
+		return changePlan
+	}
+
+	It("should refuse unplanned write calls but allow their dry runs and other tools", func() {
+		Expect(errorText(call("argocd_update_application", map[string]any{"name": "guestbook"}))).To(ContainSubstring("must be planned first"))
+		Expect(call("argocd_update_application", map[string]any{"name": "guestbook", "dryRun": true}).IsError).To(BeFalse())
+		Expect(calls).To(HaveLen(1))
+
+		planner.requirePlan = false
+		Expect(call("argocd_update_application", map[string]any{"name": "guestbook"}).IsError).To(BeFalse())
+	})
+
+	It("should plan from the tool's dry run and apply the planned arguments once", func() {
+		changePlan := plan("argocd_update_application", map[string]any{"name": "guestbook"})
+		Expect(changePlan.PlanID).NotTo(BeEmpty())
+		Expect(changePlan.StateHash).To(Equal("state-1"))
+		Expect(changePlan.SpecDiff).To(Equal("-replicas: 1\n+replicas: 2\n"))
+		Expect(calls).To(Equal([]planTestInput{{Name: "guestbook", DryRun: true}}))
+
+		result := call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID})
+		Expect(result.IsError).To(BeFalse())
+		Expect(calls[1]).To(Equal(planTestInput{Name: "guestbook"}))
+		Expect(result.StructuredContent).To(HaveKeyWithValue("tool", "argocd_update_application"))
+
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("not found"))
+		Expect(calls).To(HaveLen(2))
+	})
+
+	It("should include the results of a dry-run sync", func() {
+		changePlan := plan("argocd_sync_application", map[string]any{"name": "guestbook"})
+		Expect(changePlan.DryRunResults).To(HaveLen(1))
+		Expect(changePlan.DryRunResults[0].Message).To(Equal("configured (dry run)"))
+	})
+
+	It("should note when a tool has no dry run", func() {
+		changePlan := plan("argocd_refresh_application", map[string]any{"name": "guestbook"})
+		Expect(changePlan.Notes).To(ContainElement(ContainSubstring("has no dry run")))
+		Expect(calls).To(BeEmpty())
+	})
+
+	It("should refuse to apply once the application state changed", func() {
+		changePlan := plan("argocd_update_application", map[string]any{"name": "guestbook"})
+		hash = "state-2"
+
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("changed since plan"))
+		Expect(calls).To(HaveLen(1))
+
+		hash = "state-1"
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("not found"))
+	})
+
+	It("should keep the plan when applying waits for confirmation, passing on a confirmation code", func() {
+		changePlan := plan("argocd_update_application", map[string]any{"name": "guestbook"})
+		failing = &confirmationPendingError{message: "confirmation required"}
+
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("confirmation required"))
+
+		failing = nil
+		Expect(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID, "confirm": "ABCD-EFGH"}).IsError).To(BeFalse())
+		Expect(calls[2]).To(Equal(planTestInput{Name: "guestbook", Confirm: "ABCD-EFGH"}))
+	})
+
+	It("should discard the plan when applying fails for another reason", func() {
+		changePlan := plan("argocd_update_application", map[string]any{"name": "guestbook"})
+		failing = fmt.Errorf("connection lost")
+
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("connection lost"))
+
+		failing = nil
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("not found"))
+		Expect(calls).To(HaveLen(2))
+	})
+
+	It("should dispatch a plan once when it is applied concurrently", func() {
+		changePlan := plan("argocd_update_application", map[string]any{"name": "guestbook"})
+		entered := make(chan struct{})
+		applying = entered
+		release = make(chan struct{})
+		releaseFirst := sync.OnceFunc(func() { close(release) })
+		DeferCleanup(releaseFirst)
+
+		first := make(chan *mcp.CallToolResult, 1)
+		go func() {
+			defer GinkgoRecover()
+			first <- call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID})
+		}()
+		Eventually(entered).Should(BeClosed())
+
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("not found"))
+
+		releaseFirst()
+		Expect((<-first).IsError).To(BeFalse())
+		Expect(calls).To(HaveLen(2))
+	})
+
+	It("should expire plans", func() {
+		changePlan := plan("argocd_update_application", map[string]any{"name": "guestbook"})
+		now = now.Add(PlanTTL + time.Second)
+		Expect(errorText(call(ApplyChangeToolName, map[string]any{"planId": changePlan.PlanID}))).To(ContainSubstring("not found"))
+	})
+
+	It("should refuse to plan other tools or dry runs", func() {
+		Expect(errorText(call(PlanChangeToolName, map[string]any{"tool": "argocd_get_application", "arguments": map[string]any{"name": "guestbook"}}))).To(ContainSubstring("not a write tool"))
+		Expect(errorText(call(PlanChangeToolName, map[string]any{"tool": "argocd_update_application", "arguments": map[string]any{"name": "guestbook", "dryRun": true}}))).To(ContainSubstring("already set dryRun"))
+	})
+
+	It("should plan every write tool", func() {
+		for tool := range writeTools {
+			Expect(plannedTools).To(HaveKey(tool))
+		}
+		Expect(plannedTools).To(HaveLen(len(writeTools)))
+	})
+})
+
+var _ = Describe("applicationStateHash", func() {
+	var (
+		app     *v1alpha1.Application
+		managed []*v1alpha1.ResourceDiff
+	)
+
+	BeforeEach(func() {
+		app = &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "guestbook"},
+			Spec:       v1alpha1.ApplicationSpec{Project: "default", Source: &v1alpha1.ApplicationSource{RepoURL: "https://git", TargetRevision: "main"}},
+			Status:     v1alpha1.ApplicationStatus{Sync: v1alpha1.SyncStatus{Revision: "abc"}},
+		}
+		managed = []*v1alpha1.ResourceDiff{
+			{Kind: "Service", Namespace: "web", Name: "a", LiveState: `{"spec":{"port":80}}`, TargetState: `{"spec":{"port":80}}`},
+			{Kind: "Service", Namespace: "web", Name: "b", LiveState: `{"spec":{"port":80}}`, TargetState: `{"spec":{"port":81}}`},
+		}
+	})
+
+	It("should ignore resource order and operation state", func() {
+		hash := applicationStateHash(app, managed)
+		app.Status.OperationState = &v1alpha1.OperationState{Phase: common.OperationRunning}
+		Expect(applicationStateHash(app, []*v1alpha1.ResourceDiff{managed[1], managed[0]})).To(Equal(hash))
+	})
+
+	It("should change with the spec, the synced revision and the live state", func() {
+		hash := applicationStateHash(app, managed)
+
+		app.Spec.Source.TargetRevision = "v2"
+		Expect(applicationStateHash(app, managed)).NotTo(Equal(hash))
+		app.Spec.Source.TargetRevision = "main"
+
+		app.Status.Sync.Revision = "def"
+		Expect(applicationStateHash(app, managed)).NotTo(Equal(hash))
+		app.Status.Sync.Revision = "abc"
+
+		managed[0].LiveState = `{"spec":{"port":8080}}`
+		Expect(applicationStateHash(app, managed)).NotTo(Equal(hash))
+	})
+
+	It("should tell a missing application apart", func() {
+		Expect(applicationStateHash(nil, nil)).NotTo(Equal(applicationStateHash(app, nil)))
+	})
+})
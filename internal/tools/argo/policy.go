@@ -0,0 +1,348 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+	"template_cli/internal/policy"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// targetlessTools don't act on a single project, cluster or namespace
+var targetlessTools = map[string]bool{
+	"argocd_list_clusters":     true,
+	"argocd_list_applications": true,
+	"argocd_list_projects":     true,
+	"argocd_list_repositories": true,
+}
+
+
+// PolicyDenial is the structured content of a tool result refused by the policy
+type PolicyDenial struct {
+	Error        string              `json:"error"`
+	PolicyDenied *policy.DeniedError `json:"policyDenied"`
+}
+
+// policyArguments holds the tool arguments the policy target is resolved from
+type policyArguments struct {
+	Name                 string `json:"name"`
+	AppNamespace         string `json:"appNamespace"`
+	Project              string `json:"project"`
+	AppProject           string `json:"appProject"`
+	DestinationServer    string `json:"destinationServer"`
+	DestinationName      string `json:"destinationName"`
+	DestinationNamespace string `json:"destinationNamespace"`
+
+	// Resources a call acts on, whose namespaces are checked as well
+	Group             string              `json:"group"`
+	Version           string              `json:"version"`
+	Kind              string              `json:"kind"`
+	Namespace         string              `json:"namespace"`
+	ResourceName      string              `json:"resourceName"`
+	ResourceGroup     string              `json:"resourceGroup"`
+	ResourceKind      string              `json:"resourceKind"`
+	ResourceNamespace string              `json:"resourceNamespace"`
+	Resources         []SyncResourceInput `json:"resources"`
+	Cascade           *bool               `json:"cascade"`
+}
+
+// policyResolver looks up what the policy target of a tool call is resolved from
+type policyResolver struct {
+	application func(ctx context.Context, name, appNamespace string) (*v1alpha1.Application, error)
+	clusters    func(ctx context.Context) ([]v1alpha1.Cluster, error)
+	tree        func(ctx context.Context, name, appNamespace string) (*v1alpha1.ApplicationTree, error)
+}
+
+// NewPolicyMiddleware returns MCP middleware that checks every tool call against the policy before its handler runs
+// Applications are read live, so a call can't slip through on a stale project or destination
+func NewPolicyMiddleware(appCtx *appcontext.AppContext, pol *policy.Policy) mcp.Middleware {
+	return newPolicyMiddleware(pol, policyResolver{
+		application: func(ctx context.Context, name, appNamespace string) (*v1alpha1.Application, error) {
+			return getApplication(ctx, appCtx, name, appNamespace)
+		},
+		clusters: func(ctx context.Context) ([]v1alpha1.Cluster, error) {
+			return loadClusters(ctx, appCtx)
+		},
+		tree: func(ctx context.Context, name, appNamespace string) (*v1alpha1.ApplicationTree, error) {
+			conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create application client: %w", err)
+			}
+			defer conn.Close()
+			return appClient.ResourceTree(ctx, &application.ResourcesQuery{ApplicationName: &name, AppNamespace: &appNamespace})
+		},
+	})
+}
+
+// newPolicyMiddleware builds the policy middleware around a resolver
+func newPolicyMiddleware(pol *policy.Policy, resolver policyResolver) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			tool, rawArgs := callReq.Params.Name, callReq.Params.Arguments
+			switch tool {
+			case ApplyChangeToolName:
+				// Applying only dispatches a plan, whose call is checked when planned and again when dispatched
+				return next(ctx, method, req)
+			case PlanChangeToolName:
+				// Planning reads the application before anything is dispatched, so the planned call is checked up front
+				var planned struct {
+					Tool      string          `json:"tool"`
+					Arguments json.RawMessage `json:"arguments"`
+				}
+				if err := json.Unmarshal(rawArgs, &planned); err != nil || planned.Tool == "" {
+					return next(ctx, method, req)
+				}
+				tool, rawArgs = planned.Tool, planned.Arguments
+			}
+
+			l := log.Logger().With("component", "policy")
+			err := checkPolicy(ctx, pol, resolver, tool, rawArgs)
+
+			var denied *policy.DeniedError
+			switch {
+			case errors.As(err, &denied):
+				l.Infow("Tool call denied by policy", "tool", tool, "rule", denied.Rule, "target", denied.Target.String())
+				return policyDeniedResult(denied), nil
+			case err != nil:
+				l.Warnw("Failed to evaluate policy", "tool", tool, "error", err)
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+				}, nil
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// checkPolicy resolves what a tool call acts on and checks it against the policy
+func checkPolicy(ctx context.Context, pol *policy.Policy, resolver policyResolver, tool string, rawArgs json.RawMessage) error {
+	if targetlessTools[tool] || !pol.NeedsTarget(tool) {
+		return pol.Check(tool, policy.Target{})
+	}
+
+	var args policyArguments
+	if len(rawArgs) > 0 {
+		if err := json.Unmarshal(rawArgs, &args); err != nil {
+			return fmt.Errorf("failed to read arguments of %s for the policy check: %w", tool, err)
+		}
+	}
+
+	switch tool {
+	case "argocd_get_project":
+		return pol.Check(tool, policy.Target{Project: args.Name})
+	case "argocd_get_repository":
+		return pol.Check(tool, policy.Target{Project: args.AppProject})
+	case "argocd_create_application":
+		project := args.Project
+		if project == "" {
+			project = DefaultProject
+		}
+		target := policy.Target{
+			Project:       project,
+			ClusterServer: args.DestinationServer,
+			ClusterName:   args.DestinationName,
+			Namespace:     args.DestinationNamespace,
+		}
+		if err := resolveCluster(ctx, resolver, &target); err != nil {
+			return err
+		}
+		if err := pol.Check(tool, target); err != nil {
+			return err
+		}
+
+		// An upsert overwrites an existing application of the same name, and planning a create reads it either way,
+		// so it must be allowed where it is now as well
+		if args.Name == "" {
+			return nil
+		}
+		existing, err := resolver.application(ctx, args.Name, args.AppNamespace)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve the policy target of %s: %w", tool, err)
+		}
+		existingTarget := applicationTarget(existing)
+		if err := resolveCluster(ctx, resolver, &existingTarget); err != nil {
+			return err
+		}
+		return pol.Check(tool, existingTarget)
+	}
+
+	if args.Name == "" {
+		return fmt.Errorf("application name is required")
+	}
+	app, err := resolver.application(ctx, args.Name, args.AppNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the policy target of %s: %w", tool, err)
+	}
+	target := applicationTarget(app)
+	if err := resolveCluster(ctx, resolver, &target); err != nil {
+		return err
+	}
+	if err := pol.Check(tool, target); err != nil {
+		return err
+	}
+
+	// Resources outside the destination namespace must be allowed for their own namespace as well
+	namespaces, err := resourceNamespaces(ctx, resolver, tool, args, app)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the policy target of %s: %w", tool, err)
+	}
+	for _, namespace := range namespaces {
+		if namespace == target.Namespace {
+			continue
+		}
+		resourceTarget := target
+		resourceTarget.Namespace = namespace
+		if err := pol.Check(tool, resourceTarget); err != nil {
+			return err
+		}
+	}
+
+	// Moving an application to another namespace must be allowed for the new namespace as well
+	if tool == "argocd_update_application" && args.DestinationNamespace != "" && args.DestinationNamespace != target.Namespace {
+		target.Namespace = args.DestinationNamespace
+		return pol.Check(tool, target)
+	}
+	return nil
+}
+
+// resourceNamespaces lists the namespaces of the resources a call acts on
+// Resources given without a namespace are looked up, since the tools then match them in any namespace
+// Cluster-scoped resources have no namespace and are covered by the check of the application's destination
+func resourceNamespaces(ctx context.Context, resolver policyResolver, tool string, args policyArguments, app *v1alpha1.Application) ([]string, error) {
+	switch tool {
+	case "argocd_delete_resource":
+		// Deletion matches the namespace exactly, so a resource given without one is cluster-scoped
+		return nonEmpty(args.Namespace), nil
+	case "argocd_run_resource_action", "argocd_get_live_resource", "argocd_list_resource_actions":
+		// With a version, the resource isn't looked up and its namespace is used as given
+		return treeResourceNamespace(ctx, resolver, app, args.Group, args.Kind, args.Namespace, args.ResourceName, args.Version == "")
+	case "argocd_get_events":
+		return treeResourceNamespace(ctx, resolver, app, args.ResourceGroup, args.ResourceKind, args.ResourceNamespace, args.ResourceName, true)
+	case "argocd_get_pod_logs":
+		return nonEmpty(args.Namespace), nil
+	case "argocd_sync_application":
+		if len(args.Resources) == 0 {
+			return managedNamespaces(app, nil), nil
+		}
+		var namespaces []string
+		for _, resource := range args.Resources {
+			if resource.Namespace != "" {
+				namespaces = append(namespaces, resource.Namespace)
+				continue
+			}
+			// Argo CD syncs a resource given without a namespace in whichever namespace it is
+			namespaces = append(namespaces, managedNamespaces(app, func(status v1alpha1.ResourceStatus) bool {
+				return status.Group == resource.Group && status.Kind == resource.Kind && status.Name == resource.Name
+			})...)
+		}
+		return namespaces, nil
+	case "argocd_rollback_application":
+		return managedNamespaces(app, nil), nil
+	case "argocd_delete_application":
+		if args.Cascade == nil || *args.Cascade {
+			return managedNamespaces(app, nil), nil
+		}
+	}
+	return nil, nil
+}
+
+// treeResourceNamespace returns the namespace of a single resource, looking it up in the resource tree when none is given
+func treeResourceNamespace(ctx context.Context, resolver policyResolver, app *v1alpha1.Application, group, kind, namespace, name string, lookup bool) ([]string, error) {
+	if namespace != "" || !lookup || kind == "" || name == "" {
+		return nonEmpty(namespace), nil
+	}
+
+	tree, err := resolver.tree(ctx, app.Name, app.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource tree for application %q: %w", app.Name, err)
+	}
+	node := findResourceNode(append(tree.Nodes, tree.OrphanedNodes...), group, kind, "", name)
+	if node == nil {
+		return nil, fmt.Errorf("resource %s/%s/%s not found in application %q", group, kind, name, app.Name)
+	}
+	return nonEmpty(node.Namespace), nil
+}
+
+// managedNamespaces lists the namespaces of an application's managed resources, optionally only those matching a filter
+func managedNamespaces(app *v1alpha1.Application, match func(v1alpha1.ResourceStatus) bool) []string {
+	var namespaces []string
+	for _, resource := range app.Status.Resources {
+		if resource.Namespace == "" || slices.Contains(namespaces, resource.Namespace) {
+			continue
+		}
+		if match == nil || match(resource) {
+			namespaces = append(namespaces, resource.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// nonEmpty returns a namespace as a list, or nothing for a cluster-scoped resource
+func nonEmpty(namespace string) []string {
+	if namespace == "" {
+		return nil
+	}
+	return []string{namespace}
+}
+
+// applicationTarget describes the project and destination of an application
+func applicationTarget(app *v1alpha1.Application) policy.Target {
+	return policy.Target{
+		Project:       app.Spec.GetProject(),
+		ClusterServer: app.Spec.Destination.Server,
+		ClusterName:   app.Spec.Destination.Name,
+		Namespace:     app.Spec.Destination.Namespace,
+	}
+}
+
+// resolveCluster fills in the server or name of the target cluster when only one of them is known
+// Cluster patterns can then be written against either, whichever way the destination is specified
+func resolveCluster(ctx context.Context, resolver policyResolver, target *policy.Target) error {
+	if (target.ClusterServer == "") == (target.ClusterName == "") {
+		return nil
+	}
+
+	clusters, err := resolver.clusters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the destination cluster for the policy check: %w", err)
+	}
+	for _, cluster := range clusters {
+		switch {
+		case target.ClusterServer != "" && cluster.Server == target.ClusterServer:
+			target.ClusterName = cluster.Name
+			return nil
+		case target.ClusterName != "" && cluster.Name == target.ClusterName:
+			target.ClusterServer = cluster.Server
+			return nil
+		}
+	}
+	return nil
+}
+
+// policyDeniedResult converts a policy denial into an error result naming the rule that matched
+func policyDeniedResult(denied *policy.DeniedError) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError:           true,
+		Content:           []mcp.Content{&mcp.TextContent{Text: denied.Error()}},
+		StructuredContent: PolicyDenial{Error: denied.Error(), PolicyDenied: denied},
+	}
+}
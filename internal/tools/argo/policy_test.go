@@ -0,0 +1,264 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+
+	"template_cli/internal/policy"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testPolicy = `
+rules:
+  - name: read-anything
+    action: allow
+    tools: ["argocd_get_*", "argocd_list_*"]
+  - name: no-prod
+    action: deny
+    tools: ["*"]
+    clusters: ["*prod*"]
+  - name: team-writes
+    action: allow
+    tools: ["argocd_sync_application", "argocd_create_application", "argocd_update_application"]
+    projects: ["team-*"]
+    namespaces: ["web", "api"]
+`
+
+var _ = Describe("Policy middleware", func() {
+	var (
+		pol      *policy.Policy
+		resolver policyResolver
+		apps     map[string]*v1alpha1.Application
+		lookups  int
+	)
+
+	BeforeEach(func() {
+		var err error
+		pol, err = policy.Parse([]byte(testPolicy))
+		Expect(err).NotTo(HaveOccurred())
+
+		apps = map[string]*v1alpha1.Application{
+			"web":     policyTestApp("web", "team-a", v1alpha1.ApplicationDestination{Server: "https://staging", Namespace: "web"}),
+			"web-eu":  policyTestApp("web-eu", "team-a", v1alpha1.ApplicationDestination{Name: "eu-prod", Namespace: "web"}),
+			"billing": policyTestApp("billing", "finance", v1alpha1.ApplicationDestination{Server: "https://staging", Namespace: "web"}),
+		}
+		lookups = 0
+		resolver = policyResolver{
+			application: func(_ context.Context, name, _ string) (*v1alpha1.Application, error) {
+				lookups++
+				if app, ok := apps[name]; ok {
+					return app, nil
+				}
+				return nil, status.Errorf(codes.NotFound, "application %q not found", name)
+			},
+			clusters: func(context.Context) ([]v1alpha1.Cluster, error) {
+				return []v1alpha1.Cluster{{Name: "eu-prod", Server: "https://10.0.0.1"}}, nil
+			},
+			tree: func(context.Context, string, string) (*v1alpha1.ApplicationTree, error) {
+				return &v1alpha1.ApplicationTree{Nodes: []v1alpha1.ResourceNode{
+					{ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "Deployment", Namespace: "api", Name: "web"}},
+					{ResourceRef: v1alpha1.ResourceRef{Kind: "Pod", Namespace: "kube-system", Name: "proxy"}},
+				}}, nil
+			},
+		}
+	})
+
+	call := func(tool string, args map[string]any) (mcp.Result, bool) {
+		raw, err := json.Marshal(args)
+		Expect(err).NotTo(HaveOccurred())
+
+		called := false
+		next := func(context.Context, string, mcp.Request) (mcp.Result, error) {
+			called = true
+			return &mcp.CallToolResult{}, nil
+		}
+		handler := newPolicyMiddleware(pol, resolver)(next)
+		result, err := handler(context.Background(), "tools/call", &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: tool, Arguments: raw},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return result, called
+	}
+
+	deniedBy := func(result mcp.Result) string {
+		toolResult, ok := result.(*mcp.CallToolResult)
+		Expect(ok).To(BeTrue())
+		Expect(toolResult.IsError).To(BeTrue())
+		denial, ok := toolResult.StructuredContent.(PolicyDenial)
+		Expect(ok).To(BeTrue())
+		Expect(denial.Error).To(Equal(denial.PolicyDenied.Error()))
+		return denial.PolicyDenied.Rule
+	}
+
+	It("should pass through other methods", func() {
+		called := false
+		next := func(context.Context, string, mcp.Request) (mcp.Result, error) {
+			called = true
+			return nil, nil
+		}
+		_, err := newPolicyMiddleware(pol, resolver)(next)(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(called).To(BeTrue())
+	})
+
+	It("should not look up applications for unscoped tools", func() {
+		_, called := call("argocd_get_application", map[string]any{"name": "web"})
+		Expect(called).To(BeTrue())
+		Expect(lookups).To(BeZero())
+	})
+
+	It("should allow a call matching an allow rule", func() {
+		_, called := call("argocd_sync_application", map[string]any{"name": "web"})
+		Expect(called).To(BeTrue())
+		Expect(lookups).To(Equal(1))
+	})
+
+	It("should deny a call outside the allowed projects with the default rule", func() {
+		result, called := call("argocd_sync_application", map[string]any{"name": "billing"})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+	})
+
+	It("should resolve the cluster server of a destination given by name", func() {
+		pol.Rules[1].Clusters = []string{"https://10.*"}
+		result, called := call("argocd_sync_application", map[string]any{"name": "web-eu"})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal("no-prod"))
+	})
+
+	It("should check the new namespace when an update moves the application", func() {
+		result, called := call("argocd_update_application", map[string]any{"name": "web", "destinationNamespace": "kube-system"})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+
+		_, called = call("argocd_update_application", map[string]any{"name": "web", "destinationNamespace": "api"})
+		Expect(called).To(BeTrue())
+	})
+
+	It("should check the destination of a new application from its arguments", func() {
+		_, called := call("argocd_create_application", map[string]any{"name": "new", "project": "team-b", "destinationServer": "https://staging", "destinationNamespace": "api"})
+		Expect(called).To(BeTrue())
+
+		result, called := call("argocd_create_application", map[string]any{"name": "new", "project": "team-b", "destinationName": "eu-prod", "destinationNamespace": "api"})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal("no-prod"))
+
+		result, called = call("argocd_create_application", map[string]any{"name": "new", "destinationServer": "https://staging", "destinationNamespace": "api"})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+	})
+
+	It("should check an existing application a create would overwrite", func() {
+		args := map[string]any{"name": "web-eu", "project": "team-b", "destinationServer": "https://staging", "destinationNamespace": "api", "upsert": true}
+		result, called := call("argocd_create_application", args)
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal("no-prod"))
+
+		args["name"] = "billing"
+		result, called = call("argocd_create_application", args)
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+
+		args["name"] = "web"
+		_, called = call("argocd_create_application", args)
+		Expect(called).To(BeTrue())
+	})
+
+	Describe("resource namespaces", func() {
+		BeforeEach(func() {
+			pol.Rules[0].Tools = []string{"argocd_list_*"}
+			pol.Rules[2].Tools = append(pol.Rules[2].Tools, "argocd_delete_resource", "argocd_get_events", "argocd_run_resource_action")
+			apps["web"].Status.Resources = []v1alpha1.ResourceStatus{
+				{Group: "apps", Kind: "Deployment", Namespace: "web", Name: "web"},
+				{Group: "apps", Kind: "Deployment", Namespace: "api", Name: "api"},
+				{Kind: "Namespace", Name: "web"},
+			}
+		})
+
+		It("should check the namespace of a resource outside the destination namespace", func() {
+			result, called := call("argocd_delete_resource", map[string]any{"name": "web", "kind": "Pod", "namespace": "kube-system", "resourceName": "proxy"})
+			Expect(called).To(BeFalse())
+			Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+
+			_, called = call("argocd_delete_resource", map[string]any{"name": "web", "group": "apps", "kind": "Deployment", "namespace": "api", "resourceName": "web"})
+			Expect(called).To(BeTrue())
+		})
+
+		It("should look up resources given without a namespace", func() {
+			result, called := call("argocd_get_events", map[string]any{"name": "web", "resourceKind": "Pod", "resourceName": "proxy"})
+			Expect(called).To(BeFalse())
+			Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+
+			_, called = call("argocd_run_resource_action", map[string]any{"name": "web", "group": "apps", "kind": "Deployment", "resourceName": "web", "action": "restart"})
+			Expect(called).To(BeTrue())
+
+			result, called = call("argocd_run_resource_action", map[string]any{"name": "web", "kind": "Secret", "resourceName": "missing", "action": "restart"})
+			Expect(called).To(BeFalse())
+			Expect(result.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text).To(ContainSubstring("Secret/missing not found"))
+		})
+
+		It("should treat a resource deleted without a namespace as cluster-scoped", func() {
+			_, called := call("argocd_delete_resource", map[string]any{"name": "web", "kind": "Pod", "resourceName": "proxy"})
+			Expect(called).To(BeTrue())
+		})
+
+		It("should check every namespace a sync touches", func() {
+			_, called := call("argocd_sync_application", map[string]any{"name": "web"})
+			Expect(called).To(BeTrue())
+
+			apps["web"].Status.Resources = append(apps["web"].Status.Resources, v1alpha1.ResourceStatus{Kind: "ConfigMap", Namespace: "kube-system", Name: "proxy"})
+			result, called := call("argocd_sync_application", map[string]any{"name": "web"})
+			Expect(called).To(BeFalse())
+			Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+
+			_, called = call("argocd_sync_application", map[string]any{"name": "web", "resources": []map[string]any{{"group": "apps", "kind": "Deployment", "name": "api"}}})
+			Expect(called).To(BeTrue())
+			result, called = call("argocd_sync_application", map[string]any{"name": "web", "resources": []map[string]any{{"kind": "ConfigMap", "name": "proxy"}}})
+			Expect(called).To(BeFalse())
+			Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+		})
+	})
+
+	It("should check planned calls before they are planned", func() {
+		result, called := call(PlanChangeToolName, map[string]any{"tool": "argocd_refresh_application", "arguments": map[string]any{"name": "web-eu"}})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal("no-prod"))
+
+		result, called = call(PlanChangeToolName, map[string]any{"tool": "argocd_sync_application", "arguments": map[string]any{"name": "billing"}})
+		Expect(called).To(BeFalse())
+		Expect(deniedBy(result)).To(Equal(policy.DefaultRuleName))
+
+		_, called = call(PlanChangeToolName, map[string]any{"tool": "argocd_sync_application", "arguments": map[string]any{"name": "web"}})
+		Expect(called).To(BeTrue())
+	})
+
+	It("should leave applied plans to be checked when they are dispatched", func() {
+		_, called := call(ApplyChangeToolName, map[string]any{"planId": "abc"})
+		Expect(called).To(BeTrue())
+		Expect(lookups).To(BeZero())
+	})
+
+	It("should refuse a call whose target can't be resolved", func() {
+		result, called := call("argocd_sync_application", map[string]any{"name": "missing"})
+		Expect(called).To(BeFalse())
+		toolResult := result.(*mcp.CallToolResult)
+		Expect(toolResult.IsError).To(BeTrue())
+		Expect(toolResult.StructuredContent).To(BeNil())
+		Expect(toolResult.Content[0].(*mcp.TextContent).Text).To(ContainSubstring(`application "missing" not found`))
+	})
+})
+
+// policyTestApp builds an application in the given project and destination
+func policyTestApp(name, project string, destination v1alpha1.ApplicationDestination) *v1alpha1.Application {
+	return &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd"},
+		Spec:       v1alpha1.ApplicationSpec{Project: project, Destination: destination},
+	}
+}
@@ -0,0 +1,86 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RefreshApplicationInput defines the input parameters for refreshing an application
+type RefreshApplicationInput struct {
+	Name         string `json:"name" jsonschema:"application name"`
+	AppNamespace string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Hard         bool   `json:"hard,omitempty" jsonschema:"hard refresh: also invalidate the manifest cache and re-render from git"`
+}
+
+// RefreshApplicationOutput defines the output structure for refreshing an application
+type RefreshApplicationOutput struct {
+	RefreshType string            `json:"refreshType" jsonschema:"type of refresh performed: normal or hard"`
+	Application ApplicationDetail `json:"application" jsonschema:"application state after the refresh"`
+}
+
+// NewRefreshApplicationHandler creates a RefreshApplication handler with the provided AppContext
+func NewRefreshApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, RefreshApplicationInput) (*mcp.CallToolResult, RefreshApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input RefreshApplicationInput) (*mcp.CallToolResult, RefreshApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_refresh_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("refresh_application completed", "duration", duration)
+		}()
+
+		query, err := buildRefreshQuery(input)
+		if err != nil {
+			return nil, RefreshApplicationOutput{}, err
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, RefreshApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		// Get with a refresh waits for the controller to finish reconciling before returning
+		l.Infow("Refreshing application", "name", input.Name, "refresh_type", query.GetRefresh())
+		app, err := appClient.Get(ctx, query)
+		if err != nil {
+			return nil, RefreshApplicationOutput{}, fmt.Errorf("failed to refresh application %q: %w", input.Name, err)
+		}
+
+		// Update just this application so the rest of the cached list stays warm
+		appCtx.UpsertCachedApplication(app)
+
+		return nil, RefreshApplicationOutput{
+			RefreshType: query.GetRefresh(),
+			Application: summarizeApplication(app),
+		}, nil
+	}
+}
+
+// buildRefreshQuery validates the input and converts it into a Get query that requests a refresh
+func buildRefreshQuery(input RefreshApplicationInput) (*application.ApplicationQuery, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+
+	refreshType := string(v1alpha1.RefreshTypeNormal)
+	if input.Hard {
+		refreshType = string(v1alpha1.RefreshTypeHard)
+	}
+
+	query := &application.ApplicationQuery{
+		Name:    &input.Name,
+		Refresh: &refreshType,
+	}
+	if input.AppNamespace != "" {
+		query.AppNamespace = &input.AppNamespace
+	}
+	return query, nil
+}
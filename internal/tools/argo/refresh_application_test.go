@@ -0,0 +1,36 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Refresh Application", func() {
+	Describe("buildRefreshQuery", func() {
+		It("should require an application name", func() {
+			_, err := buildRefreshQuery(RefreshApplicationInput{})
+			Expect(err).To(MatchError(ContainSubstring("application name is required")))
+		})
+
+		DescribeTable("refresh type",
+			func(hard bool, expected string) {
+				query, err := buildRefreshQuery(RefreshApplicationInput{Name: "guestbook", Hard: hard})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(query.GetName()).To(Equal("guestbook"))
+				Expect(query.GetRefresh()).To(Equal(expected))
+			},
+			Entry("normal by default", false, "normal"),
+			Entry("hard", true, "hard"),
+		)
+
+		It("should set the application namespace only when given", func() {
+			query, err := buildRefreshQuery(RefreshApplicationInput{Name: "guestbook"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.AppNamespace).To(BeNil())
+
+			query, err = buildRefreshQuery(RefreshApplicationInput{Name: "guestbook", AppNamespace: "team-a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query.GetAppNamespace()).To(Equal("team-a"))
+		})
+	})
+})
@@ -0,0 +1,202 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RollbackApplicationInput defines the input parameters for rolling an application back to a history entry
+type RollbackApplicationInput struct {
+	Name         string `json:"name" jsonschema:"application name"`
+	AppNamespace string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	ID           int64  `json:"id" jsonschema:"history ID to roll back to, as returned by argocd_get_application_history"`
+	Prune        bool   `json:"prune,omitempty" jsonschema:"delete resources that are not part of the target revision"`
+	DryRun       bool   `json:"dryRun,omitempty" jsonschema:"preview the rollback without changing the cluster"`
+	Confirm      string `json:"confirm,omitempty" jsonschema:"one-time confirmation code for clients that can't ask the user directly; only pass a code the user gave you"`
+}
+
+// RollbackApplicationOutput defines the output structure for rolling an application back
+type RollbackApplicationOutput struct {
+	Name           string                   `json:"name"`
+	DryRun         bool                     `json:"dryRun"`
+	Current        []HistorySourceInfo      `json:"current" jsonschema:"sources and revisions currently deployed"`
+	Target         HistoryEntry             `json:"target" jsonschema:"history entry being rolled back to"`
+	Changes        []string                 `json:"changes" jsonschema:"how the target differs from what is currently deployed"`
+	Started        bool                     `json:"started" jsonschema:"true if the controller has picked up the rollback; false means it is still queued"`
+	OperationState *OperationStateInfo      `json:"operationState,omitempty" jsonschema:"state of the rollback operation, once started"`
+	Resources      []SyncResourceResultInfo `json:"resources,omitempty" jsonschema:"per-resource results of the rollback operation so far"`
+}
+
+// NewRollbackApplicationHandler creates a RollbackApplication handler with the provided AppContext
+func NewRollbackApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, RollbackApplicationInput) (*mcp.CallToolResult, RollbackApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input RollbackApplicationInput) (*mcp.CallToolResult, RollbackApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_rollback_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("rollback_application completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, RollbackApplicationOutput{}, fmt.Errorf("application name is required")
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, RollbackApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		// Validate against live state, since history and sync policy may have changed since the cache was filled
+		query := &application.ApplicationQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+		app, err := appClient.Get(ctx, query)
+		if err != nil {
+			return nil, RollbackApplicationOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+		}
+
+		target, err := validateRollback(app, input.ID)
+		if err != nil {
+			return nil, RollbackApplicationOutput{}, err
+		}
+
+		current := currentDeployment(app)
+		targetEntry := summarizeHistoryEntry(*target)
+		output := RollbackApplicationOutput{
+			Name:    input.Name,
+			DryRun:  input.DryRun,
+			Current: current,
+			Target:  targetEntry,
+			Changes: compareDeployments(current, targetEntry.Sources),
+		}
+
+		if !input.DryRun {
+			if err := confirmOperation(ctx, req, summarizeRollback(app, input, output.Changes), input.Confirm); err != nil {
+				return nil, RollbackApplicationOutput{}, err
+			}
+		}
+
+		l.Infow("Rolling back application", "name", input.Name, "id", input.ID, "prune", input.Prune, "dry_run", input.DryRun)
+		rollbackRequest := &application.ApplicationRollbackRequest{
+			Name:         &app.Name,
+			AppNamespace: &app.Namespace,
+			Id:           &input.ID,
+			Prune:        &input.Prune,
+			DryRun:       &input.DryRun,
+		}
+		if _, err := appClient.Rollback(ctx, rollbackRequest); err != nil {
+			return nil, RollbackApplicationOutput{}, fmt.Errorf("failed to roll back application %q to history ID %d: %w", input.Name, input.ID, err)
+		}
+
+		// Rollback only queues the operation, so read the application back to report its current state
+		app, err = appClient.Get(ctx, query)
+		if err != nil {
+			return nil, RollbackApplicationOutput{}, fmt.Errorf("rollback of application %q was requested but reading it back failed: %w", input.Name, err)
+		}
+
+		appCtx.UpsertCachedApplication(app)
+
+		output.Started = operationStarted(app)
+		if output.Started {
+			output.OperationState = summarizeOperationState(app.Status.OperationState)
+			output.Resources = summarizeSyncResults(app.Status.OperationState)
+		}
+		l.Infow("Rollback requested", "name", input.Name, "started", output.Started)
+
+		return nil, output, nil
+	}
+}
+
+// validateRollback checks that an application can be rolled back to the given history ID
+// Returns the matching history entry
+func validateRollback(app *v1alpha1.Application, id int64) (*v1alpha1.RevisionHistory, error) {
+	if app.Spec.SyncPolicy != nil && app.Spec.SyncPolicy.Automated != nil {
+		return nil, fmt.Errorf("application %q has auto-sync enabled; Argo CD refuses rollbacks until auto-sync is disabled", app.Name)
+	}
+
+	ids := make([]int64, 0, len(app.Status.History))
+	for i := range app.Status.History {
+		if app.Status.History[i].ID == id {
+			return &app.Status.History[i], nil
+		}
+		ids = append(ids, app.Status.History[i].ID)
+	}
+	return nil, fmt.Errorf("history ID %d not found in application %q, available IDs: %v", id, app.Name, ids)
+}
+
+// summarizeRollback describes a rollback for confirmation
+func summarizeRollback(app *v1alpha1.Application, input RollbackApplicationInput, changes []string) OperationSummary {
+	summary := newOperationSummary(fmt.Sprintf("rollback to history ID %d", input.ID), app)
+	summary.ResourceCount = len(app.Status.Resources)
+	summary.Details = append(summary.Details, changes...)
+	if input.Prune {
+		summary.Prune = pruneList(app, nil)
+		summary.Details = append(summary.Details, "Prune: resources not defined at the target revision are deleted as well")
+	}
+	return summary
+}
+
+// currentDeployment describes the sources and revisions an application is currently synced to
+func currentDeployment(app *v1alpha1.Application) []HistorySourceInfo {
+	sources := app.Spec.GetSources()
+	current := make([]HistorySourceInfo, 0, len(sources))
+	for i, source := range sources {
+		info := HistorySourceInfo{Source: summarizeSource(source)}
+		if app.Spec.HasMultipleSources() {
+			if i < len(app.Status.Sync.Revisions) {
+				info.Revision = app.Status.Sync.Revisions[i]
+			}
+		} else {
+			info.Revision = app.Status.Sync.Revision
+		}
+		current = append(current, info)
+	}
+	return current
+}
+
+// compareDeployments lists the differences between the current and target sources and revisions
+func compareDeployments(current, target []HistorySourceInfo) []string {
+	changes := make([]string, 0)
+	if len(current) != len(target) {
+		changes = append(changes, fmt.Sprintf("number of sources changes from %d to %d", len(current), len(target)))
+	}
+
+	for i := 0; i < max(len(current), len(target)); i++ {
+		label := fmt.Sprintf("source %d", i)
+		switch {
+		case i >= len(target):
+			changes = append(changes, fmt.Sprintf("%s (%s) is removed", label, current[i].Source.RepoURL))
+			continue
+		case i >= len(current):
+			changes = append(changes, fmt.Sprintf("%s (%s) is added at revision %s", label, target[i].Source.RepoURL, target[i].Revision))
+			continue
+		}
+
+		from, to := current[i], target[i]
+		changes = appendChange(changes, label, "repoURL", from.Source.RepoURL, to.Source.RepoURL)
+		changes = appendChange(changes, label, "path", from.Source.Path, to.Source.Path)
+		changes = appendChange(changes, label, "chart", from.Source.Chart, to.Source.Chart)
+		changes = appendChange(changes, label, "targetRevision", from.Source.TargetRevision, to.Source.TargetRevision)
+		changes = appendChange(changes, label, "revision", from.Revision, to.Revision)
+	}
+
+	return changes
+}
+
+// appendChange records a field change if the values differ
+func appendChange(changes []string, label, field, from, to string) []string {
+	if from == to {
+		return changes
+	}
+	return append(changes, fmt.Sprintf("%s: %s changes from %q to %q", label, field, from, to))
+}
@@ -0,0 +1,141 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Rollback Application", func() {
+	var app *v1alpha1.Application
+
+	BeforeEach(func() {
+		app = &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+			Spec: v1alpha1.ApplicationSpec{
+				Source: &v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/repo", Path: "guestbook", TargetRevision: "main"},
+			},
+			Status: v1alpha1.ApplicationStatus{
+				Sync: v1alpha1.SyncStatus{Revision: "ccc333"},
+				History: v1alpha1.RevisionHistories{
+					{ID: 1, Revision: "aaa111", Source: v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/repo", Path: "guestbook", TargetRevision: "v1.0.0"}},
+					{ID: 2, Revision: "ccc333", Source: v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/repo", Path: "guestbook", TargetRevision: "main"}},
+				},
+			},
+		}
+	})
+
+	Describe("validateRollback", func() {
+		It("should return the matching history entry", func() {
+			target, err := validateRollback(app, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(target.Revision).To(Equal("aaa111"))
+		})
+
+		It("should reject unknown history IDs and list the available ones", func() {
+			_, err := validateRollback(app, 7)
+			Expect(err).To(MatchError(ContainSubstring("history ID 7 not found")))
+			Expect(err).To(MatchError(ContainSubstring("[1 2]")))
+		})
+
+		It("should reject applications with auto-sync enabled", func() {
+			app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{Automated: &v1alpha1.SyncPolicyAutomated{}}
+			_, err := validateRollback(app, 1)
+			Expect(err).To(MatchError(ContainSubstring("auto-sync enabled")))
+		})
+
+		It("should allow a sync policy without automation", func() {
+			app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncOptions: v1alpha1.SyncOptions{"CreateNamespace=true"}}
+			_, err := validateRollback(app, 2)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("currentDeployment", func() {
+		It("should pair a single source with the synced revision", func() {
+			current := currentDeployment(app)
+			Expect(current).To(HaveLen(1))
+			Expect(current[0].Source.TargetRevision).To(Equal("main"))
+			Expect(current[0].Revision).To(Equal("ccc333"))
+		})
+
+		It("should pair multiple sources with their synced revisions", func() {
+			app.Spec.Source = nil
+			app.Spec.Sources = v1alpha1.ApplicationSources{
+				{RepoURL: "https://charts.example.com", Chart: "guestbook", TargetRevision: "1.2.3"},
+				{RepoURL: "https://github.com/example/values", Ref: "values"},
+			}
+			app.Status.Sync.Revisions = []string{"1.2.3", "ddd444"}
+
+			current := currentDeployment(app)
+			Expect(current).To(HaveLen(2))
+			Expect(current[0].Revision).To(Equal("1.2.3"))
+			Expect(current[1].Revision).To(Equal("ddd444"))
+		})
+	})
+
+	Describe("compareDeployments", func() {
+		It("should describe changed fields", func() {
+			target, err := validateRollback(app, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			changes := compareDeployments(currentDeployment(app), summarizeHistoryEntry(*target).Sources)
+			Expect(changes).To(ConsistOf(
+				`source 0: targetRevision changes from "main" to "v1.0.0"`,
+				`source 0: revision changes from "ccc333" to "aaa111"`,
+			))
+		})
+
+		It("should report no changes for the current deployment", func() {
+			target, err := validateRollback(app, 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			changes := compareDeployments(currentDeployment(app), summarizeHistoryEntry(*target).Sources)
+			Expect(changes).NotTo(BeNil())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("should describe added and removed sources", func() {
+			current := []HistorySourceInfo{{Source: ApplicationSourceInfo{RepoURL: "https://a"}, Revision: "1"}}
+			target := []HistorySourceInfo{
+				{Source: ApplicationSourceInfo{RepoURL: "https://a"}, Revision: "1"},
+				{Source: ApplicationSourceInfo{RepoURL: "https://b"}, Revision: "2"},
+			}
+
+			Expect(compareDeployments(current, target)).To(ConsistOf(
+				"number of sources changes from 1 to 2",
+				"source 1 (https://b) is added at revision 2",
+			))
+			Expect(compareDeployments(target, current)).To(ConsistOf(
+				"number of sources changes from 2 to 1",
+				"source 1 (https://b) is removed",
+			))
+		})
+	})
+
+	Describe("summarizeRollback", func() {
+		BeforeEach(func() {
+			app.Spec.Destination = v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "guestbook"}
+			app.Status.Resources = []v1alpha1.ResourceStatus{
+				{Kind: "Service", Namespace: "guestbook", Name: "web"},
+				{Kind: "Service", Namespace: "guestbook", Name: "old", RequiresPruning: true},
+			}
+		})
+
+		It("should describe the target and the changes", func() {
+			summary := summarizeRollback(app, RollbackApplicationInput{Name: "guestbook", ID: 1}, []string{"source 0: revision changes"})
+			Expect(summary.Operation).To(Equal("rollback to history ID 1"))
+			Expect(summary.ResourceCount).To(Equal(2))
+			Expect(summary.Prune).To(BeEmpty())
+			Expect(summary.Details).To(Equal([]string{"source 0: revision changes"}))
+		})
+
+		It("should list known prunes when pruning", func() {
+			summary := summarizeRollback(app, RollbackApplicationInput{Name: "guestbook", ID: 1, Prune: true}, nil)
+			Expect(summary.Prune).To(Equal([]string{"Service guestbook/old"}))
+			Expect(summary.Details).To(ConsistOf(ContainSubstring("not defined at the target revision")))
+		})
+	})
+})
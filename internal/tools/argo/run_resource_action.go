@@ -0,0 +1,135 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RunResourceActionInput defines the input parameters for running an action on a managed resource
+type RunResourceActionInput struct {
+	Name         string `json:"name" jsonschema:"application name"`
+	AppNamespace string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Group        string `json:"group,omitempty" jsonschema:"API group of the resource (empty for core resources)"`
+	Version      string `json:"version,omitempty" jsonschema:"optional API version of the resource, resolved from the resource tree when empty"`
+	Kind         string `json:"kind" jsonschema:"kind of the resource"`
+	Namespace    string `json:"namespace,omitempty" jsonschema:"namespace of the resource (empty for cluster-scoped resources)"`
+	ResourceName string `json:"resourceName" jsonschema:"name of the resource"`
+	Action       string `json:"action" jsonschema:"action to run, as returned by argocd_list_resource_actions, e.g. restart"`
+	Confirm      string `json:"confirm,omitempty" jsonschema:"one-time confirmation code for clients that can't ask the user directly; only pass a code the user gave you"`
+}
+
+// RunResourceActionOutput defines the output structure for running an action on a managed resource
+type RunResourceActionOutput struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+}
+
+// NewRunResourceActionHandler creates a RunResourceAction handler with the provided AppContext
+func NewRunResourceActionHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, RunResourceActionInput) (*mcp.CallToolResult, RunResourceActionOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input RunResourceActionInput) (*mcp.CallToolResult, RunResourceActionOutput, error) {
+		l := log.Logger().With("component", "argocd_run_resource_action")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("run_resource_action completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("application name is required")
+		}
+		if input.Kind == "" || input.ResourceName == "" || input.Action == "" {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("kind, resourceName and action are required")
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query, err := resolveResourceRequest(ctx, appClient, input.Name, input.AppNamespace, input.Group, input.Version, input.Kind, input.Namespace, input.ResourceName)
+		if err != nil {
+			return nil, RunResourceActionOutput{}, err
+		}
+
+		// Check the action against what Argo CD offers, so typos and disabled actions get a helpful error
+		actions, err := appClient.ListResourceActions(ctx, query)
+		if err != nil {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("failed to list actions of resource %s/%s/%s/%s in application %q: %w", input.Group, query.GetKind(), query.GetNamespace(), input.ResourceName, input.Name, err)
+		}
+		if err := validateResourceAction(actions.Actions, input.Action); err != nil {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("cannot run action on %s %q: %w", query.GetKind(), input.ResourceName, err)
+		}
+
+		app, err := appClient.Get(ctx, &application.ApplicationQuery{Name: query.Name, AppNamespace: query.AppNamespace})
+		if err != nil {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+		}
+		summary := newOperationSummary(fmt.Sprintf("resource action %q", input.Action), app)
+		summary.ResourceCount = 1
+		summary.Resources = []string{resourceLabel(query.GetGroup(), query.GetKind(), query.GetNamespace(), query.GetResourceName())}
+		if err := confirmOperation(ctx, req, summary, input.Confirm); err != nil {
+			return nil, RunResourceActionOutput{}, err
+		}
+
+		l.Infow("Running resource action", "name", input.Name, "kind", query.GetKind(), "resource", input.ResourceName, "action", input.Action)
+		runRequest := &application.ResourceActionRunRequest{
+			Name:         query.Name,
+			Namespace:    query.Namespace,
+			ResourceName: query.ResourceName,
+			Version:      query.Version,
+			Group:        query.Group,
+			Kind:         query.Kind,
+			Action:       &input.Action,
+			AppNamespace: query.AppNamespace,
+		}
+		if _, err := appClient.RunResourceAction(ctx, runRequest); err != nil {
+			return nil, RunResourceActionOutput{}, fmt.Errorf("failed to run action %q on %s %q in application %q: %w", input.Action, query.GetKind(), input.ResourceName, input.Name, err)
+		}
+
+		return nil, RunResourceActionOutput{
+			Group:     input.Group,
+			Version:   query.GetVersion(),
+			Kind:      query.GetKind(),
+			Namespace: query.GetNamespace(),
+			Name:      input.ResourceName,
+			Action:    input.Action,
+		}, nil
+	}
+}
+
+// validateResourceAction checks that an action is available and enabled for a resource
+func validateResourceAction(actions []*v1alpha1.ResourceAction, name string) error {
+	available := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if action == nil {
+			continue
+		}
+		if action.Name == name {
+			if action.Disabled {
+				return fmt.Errorf("action %q is currently disabled", name)
+			}
+			return nil
+		}
+		// Disabled actions would fail as well, so only enabled ones are offered
+		if !action.Disabled {
+			available = append(available, action.Name)
+		}
+	}
+	if len(available) == 0 {
+		return fmt.Errorf("action %q is not available and no actions are currently enabled", name)
+	}
+	return fmt.Errorf("action %q is not available, expected one of %v", name, available)
+}
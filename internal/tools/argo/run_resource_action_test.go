@@ -0,0 +1,37 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("Run Resource Action", func() {
+	Describe("validateResourceAction", func() {
+		actions := []*v1alpha1.ResourceAction{
+			{Name: "restart"},
+			{Name: "pause"},
+			{Name: "resume", Disabled: true},
+		}
+
+		It("should accept an available action", func() {
+			Expect(validateResourceAction(actions, "restart")).To(Succeed())
+		})
+
+		It("should reject a disabled action", func() {
+			Expect(validateResourceAction(actions, "resume")).To(MatchError(ContainSubstring(`action "resume" is currently disabled`)))
+		})
+
+		It("should list only the enabled actions for an unknown one", func() {
+			err := validateResourceAction(actions, "restrat")
+			Expect(err).To(MatchError(ContainSubstring(`action "restrat" is not available`)))
+			Expect(err).To(MatchError(ContainSubstring("[restart pause]")))
+		})
+
+		It("should reject any action when none are enabled", func() {
+			Expect(validateResourceAction(nil, "restart")).To(MatchError(ContainSubstring("no actions are currently enabled")))
+			Expect(validateResourceAction([]*v1alpha1.ResourceAction{{Name: "resume", Disabled: true}}, "restart")).To(MatchError(ContainSubstring("no actions are currently enabled")))
+		})
+	})
+})
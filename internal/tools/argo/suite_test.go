@@ -5,6 +5,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"template_cli/internal/log"
 )
 
 func TestArgoTools(t *testing.T) {
@@ -12,3 +14,11 @@ func TestArgoTools(t *testing.T) {
 	RunSpecs(t, "Argo Tools Suite")
 }
 
+var _ = BeforeSuite(func() {
+	// Initialize logger for tests that run middleware
+	err := log.Init()
+	if err != nil {
+		// Log initialization may fail in test environment, which is acceptable
+		GinkgoWriter.Printf("Warning: Failed to initialize logger: %v\n", err)
+	}
+})
@@ -0,0 +1,270 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// SyncStrategyApply syncs with kubectl apply, skipping hooks
+	SyncStrategyApply = "apply"
+
+	// SyncStrategyHook syncs with kubectl apply and runs hooks, the Argo CD default
+	SyncStrategyHook = "hook"
+)
+
+// SyncApplicationInput defines the input parameters for syncing an application
+type SyncApplicationInput struct {
+	Name         string              `json:"name" jsonschema:"application name"`
+	AppNamespace string              `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Revision     string              `json:"revision,omitempty" jsonschema:"optional revision to sync to, defaults to the application's target revision"`
+	Prune        bool                `json:"prune,omitempty" jsonschema:"delete resources that are no longer defined in git"`
+	DryRun       bool                `json:"dryRun,omitempty" jsonschema:"preview the sync without changing the cluster"`
+	Resources    []SyncResourceInput `json:"resources,omitempty" jsonschema:"optional subset of resources to sync, defaults to all"`
+	SyncOptions  []string            `json:"syncOptions,omitempty" jsonschema:"optional sync options in Key=value form, e.g. ServerSideApply=true or Replace=true"`
+	Strategy     string              `json:"strategy,omitempty" jsonschema:"sync strategy: hook (default, runs hooks) or apply (skips hooks)"`
+	Force        bool                `json:"force,omitempty" jsonschema:"use a force apply, deleting and re-creating resources that can't be patched"`
+	Confirm      string              `json:"confirm,omitempty" jsonschema:"one-time confirmation code for clients that can't ask the user directly; only pass a code the user gave you"`
+}
+
+// SyncResourceInput identifies a resource to include in a partial sync
+type SyncResourceInput struct {
+	Group     string `json:"group,omitempty" jsonschema:"API group (empty for core resources)"`
+	Kind      string `json:"kind" jsonschema:"resource kind"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"resource namespace (empty for cluster-scoped resources)"`
+	Name      string `json:"name" jsonschema:"resource name"`
+}
+
+// SyncApplicationOutput defines the output structure for syncing an application
+type SyncApplicationOutput struct {
+	Name           string                   `json:"name"`
+	DryRun         bool                     `json:"dryRun"`
+	Started        bool                     `json:"started" jsonschema:"true if the controller has picked up the sync; false means it is still queued"`
+	OperationState *OperationStateInfo      `json:"operationState,omitempty" jsonschema:"state of the sync operation, once started"`
+	Resources      []SyncResourceResultInfo `json:"resources,omitempty" jsonschema:"per-resource results of the sync operation so far"`
+}
+
+// SyncResourceResultInfo describes the result of syncing one resource
+type SyncResourceResultInfo struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Status    string `json:"status,omitempty"`
+	Message   string `json:"message,omitempty"`
+	HookType  string `json:"hookType,omitempty"`
+	HookPhase string `json:"hookPhase,omitempty"`
+	SyncPhase string `json:"syncPhase,omitempty"`
+}
+
+// NewSyncApplicationHandler creates a SyncApplication handler with the provided AppContext
+func NewSyncApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, SyncApplicationInput) (*mcp.CallToolResult, SyncApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input SyncApplicationInput) (*mcp.CallToolResult, SyncApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_sync_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("sync_application completed", "duration", duration)
+		}()
+
+		syncRequest, err := buildSyncRequest(input)
+		if err != nil {
+			return nil, SyncApplicationOutput{}, err
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, SyncApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ApplicationQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+
+		// A pruning sync deletes resources, so the user has to confirm exactly which ones first
+		if input.Prune && !input.DryRun {
+			app, err := appClient.Get(ctx, query)
+			if err != nil {
+				return nil, SyncApplicationOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+			}
+			if err := confirmOperation(ctx, req, summarizePruningSync(app, input), input.Confirm); err != nil {
+				return nil, SyncApplicationOutput{}, err
+			}
+		}
+
+		l.Infow("Syncing application", "name", input.Name, "revision", input.Revision, "prune", input.Prune, "dry_run", input.DryRun, "resources", len(input.Resources))
+		if _, err := appClient.Sync(ctx, syncRequest); err != nil {
+			return nil, SyncApplicationOutput{}, fmt.Errorf("failed to sync application %q: %w", input.Name, err)
+		}
+
+		// Sync only queues the operation, so read the application back to report its current state
+		app, err := appClient.Get(ctx, query)
+		if err != nil {
+			return nil, SyncApplicationOutput{}, fmt.Errorf("sync of application %q was requested but reading it back failed: %w", input.Name, err)
+		}
+
+		appCtx.UpsertCachedApplication(app)
+
+		output := SyncApplicationOutput{
+			Name:    input.Name,
+			DryRun:  input.DryRun,
+			Started: operationStarted(app),
+		}
+		if output.Started {
+			output.OperationState = summarizeOperationState(app.Status.OperationState)
+			output.Resources = summarizeSyncResults(app.Status.OperationState)
+		}
+		l.Infow("Sync requested", "name", input.Name, "started", output.Started)
+
+		return nil, output, nil
+	}
+}
+
+// buildSyncRequest validates the input and converts it into a sync request
+func buildSyncRequest(input SyncApplicationInput) (*application.ApplicationSyncRequest, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+
+	syncRequest := &application.ApplicationSyncRequest{
+		Name:   &input.Name,
+		Prune:  &input.Prune,
+		DryRun: &input.DryRun,
+	}
+	if input.AppNamespace != "" {
+		syncRequest.AppNamespace = &input.AppNamespace
+	}
+	if input.Revision != "" {
+		syncRequest.Revision = &input.Revision
+	}
+
+	for _, resource := range input.Resources {
+		if resource.Kind == "" || resource.Name == "" {
+			return nil, fmt.Errorf("each resource requires a kind and a name")
+		}
+		syncRequest.Resources = append(syncRequest.Resources, &v1alpha1.SyncOperationResource{
+			Group:     resource.Group,
+			Kind:      resource.Kind,
+			Namespace: resource.Namespace,
+			Name:      resource.Name,
+		})
+	}
+
+	if len(input.SyncOptions) > 0 {
+		for _, option := range input.SyncOptions {
+			if !strings.Contains(option, "=") {
+				return nil, fmt.Errorf("invalid sync option %q, expected Key=value", option)
+			}
+		}
+		syncRequest.SyncOptions = &application.SyncOptions{Items: input.SyncOptions}
+	}
+
+	switch input.Strategy {
+	case "", SyncStrategyHook:
+		// Leaving the strategy unset lets Argo CD use its hook default, but force still has to be passed through
+		if input.Force {
+			syncRequest.Strategy = &v1alpha1.SyncStrategy{Hook: &v1alpha1.SyncStrategyHook{SyncStrategyApply: v1alpha1.SyncStrategyApply{Force: true}}}
+		}
+	case SyncStrategyApply:
+		syncRequest.Strategy = &v1alpha1.SyncStrategy{Apply: &v1alpha1.SyncStrategyApply{Force: input.Force}}
+	default:
+		return nil, fmt.Errorf("unsupported strategy %q, expected %q or %q", input.Strategy, SyncStrategyHook, SyncStrategyApply)
+	}
+
+	return syncRequest, nil
+}
+
+// summarizePruningSync describes a sync with prune for confirmation
+func summarizePruningSync(app *v1alpha1.Application, input SyncApplicationInput) OperationSummary {
+	summary := newOperationSummary("sync with prune", app)
+	summary.ResourceCount = len(app.Status.Resources)
+	if len(input.Resources) > 0 {
+		summary.ResourceCount = len(input.Resources)
+		for _, resource := range input.Resources {
+			summary.Resources = append(summary.Resources, resourceLabel(resource.Group, resource.Kind, resource.Namespace, resource.Name))
+		}
+	}
+	summary.Prune = pruneList(app, input.Resources)
+
+	revision := input.Revision
+	if revision == "" {
+		revision = "target revision"
+	}
+	summary.Details = append(summary.Details, fmt.Sprintf("Revision: %s", revision))
+	if input.Force {
+		summary.Details = append(summary.Details, "Force: resources that can't be patched are deleted and re-created")
+	}
+	return summary
+}
+
+// pruneList lists the resources Argo CD would prune, limited to the given subset if there is one
+func pruneList(app *v1alpha1.Application, subset []SyncResourceInput) []string {
+	var prune []string
+	for _, resource := range app.Status.Resources {
+		if !resource.RequiresPruning {
+			continue
+		}
+		if len(subset) > 0 && !inSyncSubset(resource, subset) {
+			continue
+		}
+		prune = append(prune, resourceLabel(resource.Group, resource.Kind, resource.Namespace, resource.Name))
+	}
+	return prune
+}
+
+// inSyncSubset reports whether a resource is part of a partial sync
+func inSyncSubset(resource v1alpha1.ResourceStatus, subset []SyncResourceInput) bool {
+	for _, selected := range subset {
+		if selected.Group == resource.Group && selected.Kind == resource.Kind && selected.Namespace == resource.Namespace && selected.Name == resource.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// operationStarted reports whether the controller has picked up the application's requested operation
+// While an operation is queued, Operation is set but OperationState still describes the previous, completed one
+func operationStarted(app *v1alpha1.Application) bool {
+	state := app.Status.OperationState
+	if state == nil {
+		return false
+	}
+	return app.Operation == nil || !state.Phase.Completed()
+}
+
+// summarizeSyncResults converts the per-resource results of a sync operation
+func summarizeSyncResults(state *v1alpha1.OperationState) []SyncResourceResultInfo {
+	if state == nil || state.SyncResult == nil {
+		return nil
+	}
+
+	results := make([]SyncResourceResultInfo, 0, len(state.SyncResult.Resources))
+	for _, result := range state.SyncResult.Resources {
+		if result == nil {
+			continue
+		}
+		results = append(results, SyncResourceResultInfo{
+			Group:     result.Group,
+			Kind:      result.Kind,
+			Namespace: result.Namespace,
+			Name:      result.Name,
+			Status:    string(result.Status),
+			Message:   result.Message,
+			HookType:  string(result.HookType),
+			HookPhase: string(result.HookPhase),
+			SyncPhase: string(result.SyncPhase),
+		})
+	}
+	return results
+}
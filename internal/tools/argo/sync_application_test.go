@@ -0,0 +1,167 @@
+package argo
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+var _ = Describe("Sync Application", func() {
+	Describe("buildSyncRequest", func() {
+		It("should require an application name", func() {
+			_, err := buildSyncRequest(SyncApplicationInput{})
+			Expect(err).To(MatchError(ContainSubstring("application name is required")))
+		})
+
+		It("should build a minimal request", func() {
+			request, err := buildSyncRequest(SyncApplicationInput{Name: "guestbook"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(request.GetName()).To(Equal("guestbook"))
+			Expect(request.GetPrune()).To(BeFalse())
+			Expect(request.GetDryRun()).To(BeFalse())
+			Expect(request.Revision).To(BeNil())
+			Expect(request.AppNamespace).To(BeNil())
+			Expect(request.Strategy).To(BeNil())
+			Expect(request.SyncOptions).To(BeNil())
+		})
+
+		It("should pass through revision, prune, dry-run and resources", func() {
+			request, err := buildSyncRequest(SyncApplicationInput{
+				Name:         "guestbook",
+				AppNamespace: "team-a",
+				Revision:     "v1.2.3",
+				Prune:        true,
+				DryRun:       true,
+				Resources:    []SyncResourceInput{{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(request.GetAppNamespace()).To(Equal("team-a"))
+			Expect(request.GetRevision()).To(Equal("v1.2.3"))
+			Expect(request.GetPrune()).To(BeTrue())
+			Expect(request.GetDryRun()).To(BeTrue())
+			Expect(request.Resources).To(ConsistOf(&v1alpha1.SyncOperationResource{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web"}))
+		})
+
+		It("should reject resources without a kind or name", func() {
+			_, err := buildSyncRequest(SyncApplicationInput{Name: "guestbook", Resources: []SyncResourceInput{{Kind: "Deployment"}}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should pass through sync options", func() {
+			request, err := buildSyncRequest(SyncApplicationInput{Name: "guestbook", SyncOptions: []string{"ServerSideApply=true", "Replace=true"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(request.SyncOptions.Items).To(Equal([]string{"ServerSideApply=true", "Replace=true"}))
+		})
+
+		It("should reject malformed sync options", func() {
+			_, err := buildSyncRequest(SyncApplicationInput{Name: "guestbook", SyncOptions: []string{"ServerSideApply"}})
+			Expect(err).To(MatchError(ContainSubstring("Key=value")))
+		})
+
+		DescribeTable("strategy",
+			func(strategy string, force bool, expectApply, expectHook bool) {
+				request, err := buildSyncRequest(SyncApplicationInput{Name: "guestbook", Strategy: strategy, Force: force})
+				Expect(err).NotTo(HaveOccurred())
+				if !expectApply && !expectHook {
+					Expect(request.Strategy).To(BeNil())
+					return
+				}
+				Expect(request.Strategy.Apply != nil).To(Equal(expectApply))
+				Expect(request.Strategy.Hook != nil).To(Equal(expectHook))
+				Expect(request.Strategy.Force()).To(Equal(force))
+			},
+			Entry("default leaves strategy unset", "", false, false, false),
+			Entry("hook leaves strategy unset", "hook", false, false, false),
+			Entry("forced hook", "hook", true, false, true),
+			Entry("apply", "apply", false, true, false),
+			Entry("forced apply", "apply", true, true, false),
+		)
+
+		It("should reject unknown strategies", func() {
+			_, err := buildSyncRequest(SyncApplicationInput{Name: "guestbook", Strategy: "replace"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("operationStarted", func() {
+		DescribeTable("operation lifecycle",
+			func(operation *v1alpha1.Operation, state *v1alpha1.OperationState, expected bool) {
+				app := &v1alpha1.Application{Operation: operation}
+				app.Status.OperationState = state
+				Expect(operationStarted(app)).To(Equal(expected))
+			},
+			Entry("no operation state yet", &v1alpha1.Operation{}, nil, false),
+			Entry("queued behind a completed operation", &v1alpha1.Operation{}, &v1alpha1.OperationState{Phase: "Succeeded"}, false),
+			Entry("running", &v1alpha1.Operation{}, &v1alpha1.OperationState{Phase: "Running"}, true),
+			Entry("already finished", nil, &v1alpha1.OperationState{Phase: "Succeeded"}, true),
+		)
+	})
+
+	Describe("summarizeSyncResults", func() {
+		It("should return nil without a sync result", func() {
+			Expect(summarizeSyncResults(nil)).To(BeNil())
+			Expect(summarizeSyncResults(&v1alpha1.OperationState{})).To(BeNil())
+		})
+
+		It("should convert resource results", func() {
+			state := &v1alpha1.OperationState{
+				SyncResult: &v1alpha1.SyncOperationResult{
+					Resources: v1alpha1.ResourceResults{
+						{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web", Status: "Synced", Message: "deployment.apps/web configured", SyncPhase: "Sync"},
+						nil,
+						{Kind: "Job", Namespace: "guestbook", Name: "migrate", HookType: "PreSync", HookPhase: "Running"},
+					},
+				},
+			}
+
+			results := summarizeSyncResults(state)
+			Expect(results).To(HaveLen(2))
+			Expect(results[0]).To(Equal(SyncResourceResultInfo{
+				Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web",
+				Status: "Synced", Message: "deployment.apps/web configured", SyncPhase: "Sync",
+			}))
+			Expect(results[1].HookType).To(Equal("PreSync"))
+			Expect(results[1].HookPhase).To(Equal("Running"))
+		})
+	})
+
+	Describe("summarizePruningSync", func() {
+		var app *v1alpha1.Application
+
+		BeforeEach(func() {
+			app = &v1alpha1.Application{}
+			app.Name = "guestbook"
+			app.Spec.Destination = v1alpha1.ApplicationDestination{Name: "in-cluster", Namespace: "guestbook"}
+			app.Status.Resources = []v1alpha1.ResourceStatus{
+				{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "web"},
+				{Kind: "Service", Namespace: "guestbook", Name: "old", RequiresPruning: true},
+				{Kind: "ConfigMap", Namespace: "guestbook", Name: "stale", RequiresPruning: true},
+			}
+		})
+
+		It("should list all resources that require pruning", func() {
+			summary := summarizePruningSync(app, SyncApplicationInput{Name: "guestbook", Prune: true})
+			Expect(summary.Cluster).To(Equal("in-cluster"))
+			Expect(summary.ResourceCount).To(Equal(3))
+			Expect(summary.Prune).To(Equal([]string{"Service guestbook/old", "ConfigMap guestbook/stale"}))
+			Expect(summary.Details).To(Equal([]string{"Revision: target revision"}))
+		})
+
+		It("should limit a partial sync to the selected resources", func() {
+			input := SyncApplicationInput{
+				Name:      "guestbook",
+				Prune:     true,
+				Revision:  "v2",
+				Force:     true,
+				Resources: []SyncResourceInput{{Kind: "Service", Namespace: "guestbook", Name: "old"}},
+			}
+			summary := summarizePruningSync(app, input)
+			Expect(summary.ResourceCount).To(Equal(1))
+			Expect(summary.Resources).To(Equal([]string{"Service guestbook/old"}))
+			Expect(summary.Prune).To(Equal([]string{"Service guestbook/old"}))
+			Expect(summary.Details).To(HaveLen(2))
+			Expect(summary.Details[0]).To(Equal("Revision: v2"))
+		})
+	})
+})
@@ -0,0 +1,169 @@
+package argo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// DefaultTerminateTimeoutSeconds is how long to wait for a terminated operation to finish when no timeout is given
+	DefaultTerminateTimeoutSeconds = 30
+
+	// MaxTerminateTimeoutSeconds is the longest a caller may wait for a terminated operation to finish
+	MaxTerminateTimeoutSeconds = 300
+)
+
+// TerminateOperationInput defines the input parameters for terminating an application's running operation
+type TerminateOperationInput struct {
+	Name           string `json:"name" jsonschema:"application name"`
+	AppNamespace   string `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty" jsonschema:"optional time to wait for the operation to stop after terminating (default 30, max 300)"`
+}
+
+// TerminateOperationOutput defines the output structure for terminating an application's running operation
+type TerminateOperationOutput struct {
+	Name       string                   `json:"name"`
+	Before     *OperationStateInfo      `json:"before,omitempty" jsonschema:"operation state before terminating; absent if the operation was still queued"`
+	Hooks      []SyncResourceResultInfo `json:"hooks,omitempty" jsonschema:"hook status before terminating"`
+	FinalState *OperationStateInfo      `json:"finalState,omitempty" jsonschema:"operation state after terminating"`
+	Confirmed  bool                     `json:"confirmed" jsonschema:"true if the operation was seen to stop within the timeout"`
+}
+
+// NewTerminateOperationHandler creates a TerminateOperation handler with the provided AppContext
+func NewTerminateOperationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, TerminateOperationInput) (*mcp.CallToolResult, TerminateOperationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input TerminateOperationInput) (*mcp.CallToolResult, TerminateOperationOutput, error) {
+		l := log.Logger().With("component", "argocd_terminate_operation")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("terminate_operation completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("application name is required")
+		}
+
+		timeoutSeconds := input.TimeoutSeconds
+		if timeoutSeconds <= 0 {
+			timeoutSeconds = DefaultTerminateTimeoutSeconds
+		}
+		timeoutSeconds = min(timeoutSeconds, MaxTerminateTimeoutSeconds)
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ApplicationQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+		app, err := appClient.Get(ctx, query)
+		if err != nil {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+		}
+		if !operationInProgress(app) {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("application %q has no operation in progress", input.Name)
+		}
+
+		// A queued operation has no operation state of its own yet
+		output := TerminateOperationOutput{Name: input.Name}
+		if operationStarted(app) {
+			output.Before = summarizeOperationState(app.Status.OperationState)
+			output.Hooks = filterHookResults(summarizeSyncResults(app.Status.OperationState))
+			l.Infow("Terminating operation", "name", input.Name, "phase", app.Status.OperationState.Phase)
+		} else {
+			l.Infow("Terminating queued operation", "name", input.Name)
+		}
+		terminateRequest := &application.OperationTerminateRequest{
+			Name:         &app.Name,
+			AppNamespace: &app.Namespace,
+		}
+		if _, err := appClient.TerminateOperation(ctx, terminateRequest); err != nil {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("failed to terminate operation of application %q: %w", input.Name, err)
+		}
+
+		// Termination is asynchronous, so watch the application until the operation has stopped
+		watchCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+		defer cancel()
+
+		stream, err := appClient.Watch(watchCtx, query)
+		if err != nil {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("operation of application %q was terminated but watching it failed: %w", input.Name, err)
+		}
+
+		final, confirmed, err := watchApplication(stream.Recv, func(app *v1alpha1.Application) bool {
+			return !operationInProgress(app)
+		})
+		if err != nil && watchCtx.Err() == nil {
+			return nil, TerminateOperationOutput{}, fmt.Errorf("operation of application %q was terminated but watching it failed: %w", input.Name, err)
+		}
+
+		if final != nil {
+			appCtx.UpsertCachedApplication(final)
+			if final.Status.OperationState != nil {
+				output.FinalState = summarizeOperationState(final.Status.OperationState)
+			}
+		}
+		output.Confirmed = confirmed
+		l.Infow("Operation terminated", "name", input.Name, "confirmed", confirmed)
+
+		return nil, output, nil
+	}
+}
+
+// operationInProgress reports whether an application has an operation that is queued or running
+func operationInProgress(app *v1alpha1.Application) bool {
+	if app.Operation != nil {
+		return true
+	}
+	state := app.Status.OperationState
+	return state != nil && !state.Phase.Completed()
+}
+
+// filterHookResults keeps only the results of hook resources
+func filterHookResults(results []SyncResourceResultInfo) []SyncResourceResultInfo {
+	var hooks []SyncResourceResultInfo
+	for _, result := range results {
+		if result.HookType != "" {
+			hooks = append(hooks, result)
+		}
+	}
+	return hooks
+}
+
+// watchApplication reads application watch events until done returns true or the stream ends
+// Returns the last application seen and whether done was satisfied
+func watchApplication(recv func() (*v1alpha1.ApplicationWatchEvent, error), done func(*v1alpha1.Application) bool) (*v1alpha1.Application, bool, error) {
+	var last *v1alpha1.Application
+	for {
+		event, err := recv()
+		if errors.Is(err, io.EOF) {
+			return last, false, nil
+		}
+		if err != nil {
+			return last, false, err
+		}
+
+		app := event.Application
+		if event.Type == watch.Deleted {
+			return last, false, fmt.Errorf("application %q was deleted", app.Name)
+		}
+		last = &app
+		if done(last) {
+			return last, true, nil
+		}
+	}
+}
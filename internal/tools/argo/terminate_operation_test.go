@@ -0,0 +1,180 @@
+package argo
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"template_cli/internal/appcontext"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// terminateArgoClient hands out a terminateAppClient
+type terminateArgoClient struct {
+	apiclient.Client
+	app *terminateAppClient
+}
+
+func (c *terminateArgoClient) NewApplicationClient() (io.Closer, application.ApplicationServiceClient, error) {
+	return io.NopCloser(nil), c.app, nil
+}
+
+// terminateAppClient serves one application and replays watch events for it
+type terminateAppClient struct {
+	application.ApplicationServiceClient
+	app        *v1alpha1.Application
+	events     []*v1alpha1.ApplicationWatchEvent
+	terminated bool
+}
+
+func (c *terminateAppClient) Get(context.Context, *application.ApplicationQuery, ...grpc.CallOption) (*v1alpha1.Application, error) {
+	return c.app.DeepCopy(), nil
+}
+
+func (c *terminateAppClient) TerminateOperation(context.Context, *application.OperationTerminateRequest, ...grpc.CallOption) (*application.OperationTerminateResponse, error) {
+	c.terminated = true
+	return &application.OperationTerminateResponse{}, nil
+}
+
+func (c *terminateAppClient) Watch(context.Context, *application.ApplicationQuery, ...grpc.CallOption) (application.ApplicationService_WatchClient, error) {
+	return &fakeWatchClient{recv: fakeWatchStream(c.events...)}, nil
+}
+
+// fakeWatchClient is a watch stream backed by a recv function
+type fakeWatchClient struct {
+	grpc.ClientStream
+	recv func() (*v1alpha1.ApplicationWatchEvent, error)
+}
+
+func (c *fakeWatchClient) Recv() (*v1alpha1.ApplicationWatchEvent, error) {
+	return c.recv()
+}
+
+// fakeWatchStream returns a recv function that yields the given events and then io.EOF
+func fakeWatchStream(events ...*v1alpha1.ApplicationWatchEvent) func() (*v1alpha1.ApplicationWatchEvent, error) {
+	i := 0
+	return func() (*v1alpha1.ApplicationWatchEvent, error) {
+		if i >= len(events) {
+			return nil, io.EOF
+		}
+		event := events[i]
+		i++
+		return event, nil
+	}
+}
+
+// watchEvent builds a Modified watch event for an application in the given operation phase
+func watchEvent(phase string, operation *v1alpha1.Operation) *v1alpha1.ApplicationWatchEvent {
+	app := v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook"},
+		Operation:  operation,
+	}
+	if phase != "" {
+		app.Status.OperationState = &v1alpha1.OperationState{Phase: synccommon.OperationPhase(phase)}
+	}
+	return &v1alpha1.ApplicationWatchEvent{Type: watch.Modified, Application: app}
+}
+
+var _ = Describe("Terminate Operation", func() {
+	DescribeTable("operationInProgress",
+		func(operation *v1alpha1.Operation, phase string, expected bool) {
+			app := &watchEvent(phase, operation).Application
+			Expect(operationInProgress(app)).To(Equal(expected))
+		},
+		Entry("no operation at all", nil, "", false),
+		Entry("queued", &v1alpha1.Operation{}, "Succeeded", true),
+		Entry("running", &v1alpha1.Operation{}, "Running", true),
+		Entry("terminating", nil, "Terminating", true),
+		Entry("finished", nil, "Failed", false),
+	)
+
+	Describe("handler", func() {
+		It("should terminate an operation that is queued but not started", func() {
+			queued := watchEvent("", &v1alpha1.Operation{})
+			appClient := &terminateAppClient{app: &queued.Application, events: []*v1alpha1.ApplicationWatchEvent{watchEvent("Terminating", nil), watchEvent("Failed", nil)}}
+			handler := NewTerminateOperationHandler(&appcontext.AppContext{ArgoClient: &terminateArgoClient{app: appClient}})
+
+			_, output, err := handler(context.Background(), nil, TerminateOperationInput{Name: "guestbook"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(appClient.terminated).To(BeTrue())
+			Expect(output.Before == nil).To(BeTrue())
+			Expect(output.Confirmed).To(BeTrue())
+			Expect(string(output.FinalState.Phase)).To(Equal("Failed"))
+		})
+	})
+
+	Describe("filterHookResults", func() {
+		It("should keep only hooks", func() {
+			results := []SyncResourceResultInfo{
+				{Kind: "Deployment", Name: "web"},
+				{Kind: "Job", Name: "migrate", HookType: "PreSync", HookPhase: "Running"},
+			}
+			Expect(filterHookResults(results)).To(ConsistOf(results[1]))
+			Expect(filterHookResults(nil)).To(BeEmpty())
+		})
+	})
+
+	Describe("watchApplication", func() {
+		notInProgress := func(app *v1alpha1.Application) bool {
+			return !operationInProgress(app)
+		}
+
+		It("should stop once the condition is met", func() {
+			recv := fakeWatchStream(
+				watchEvent("Running", &v1alpha1.Operation{}),
+				watchEvent("Terminating", nil),
+				watchEvent("Failed", nil),
+				watchEvent("Running", &v1alpha1.Operation{}),
+			)
+
+			final, done, err := watchApplication(recv, notInProgress)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(string(final.Status.OperationState.Phase)).To(Equal("Failed"))
+		})
+
+		It("should return the last application when the stream ends", func() {
+			recv := fakeWatchStream(watchEvent("Running", &v1alpha1.Operation{}), watchEvent("Terminating", nil))
+
+			final, done, err := watchApplication(recv, notInProgress)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(string(final.Status.OperationState.Phase)).To(Equal("Terminating"))
+		})
+
+		It("should return stream errors with the last application", func() {
+			first := true
+			recv := func() (*v1alpha1.ApplicationWatchEvent, error) {
+				if first {
+					first = false
+					return watchEvent("Running", &v1alpha1.Operation{}), nil
+				}
+				return nil, errors.New("deadline exceeded")
+			}
+
+			final, done, err := watchApplication(recv, notInProgress)
+			Expect(err).To(MatchError("deadline exceeded"))
+			Expect(done).To(BeFalse())
+			Expect(final).NotTo(BeNil())
+		})
+
+		It("should fail when the application is deleted", func() {
+			deleted := watchEvent("Running", &v1alpha1.Operation{})
+			deleted.Type = watch.Deleted
+
+			_, done, err := watchApplication(fakeWatchStream(deleted), notInProgress)
+			Expect(err).To(MatchError(ContainSubstring("was deleted")))
+			Expect(done).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,316 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SyncPolicyAutomated enables automated sync
+	SyncPolicyAutomated = "automated"
+
+	// SyncPolicyNone disables automated sync
+	SyncPolicyNone = "none"
+)
+
+// UpdateApplicationInput defines the input parameters for editing an application's spec, like argocd app set
+type UpdateApplicationInput struct {
+	Name                 string   `json:"name" jsonschema:"application name"`
+	AppNamespace         string   `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	SourcePosition       int      `json:"sourcePosition,omitempty" jsonschema:"1-based position of the source to edit, required for multi-source applications"`
+	Revision             string   `json:"revision,omitempty" jsonschema:"new target revision of the source"`
+	HelmParameters       []string `json:"helmParameters,omitempty" jsonschema:"Helm parameters to set in name=value form, replacing existing parameters with the same name"`
+	HelmValueFiles       []string `json:"helmValueFiles,omitempty" jsonschema:"Helm value files, replacing the current list"`
+	KustomizeImages      []string `json:"kustomizeImages,omitempty" jsonschema:"Kustomize image overrides, e.g. nginx=nginx:1.27, replacing existing overrides for the same image"`
+	KustomizeNamePrefix  *string  `json:"kustomizeNamePrefix,omitempty" jsonschema:"Kustomize name prefix; an empty string removes it"`
+	SyncPolicy           string   `json:"syncPolicy,omitempty" jsonschema:"automated to enable automated sync or none to disable it"`
+	AutoPrune            *bool    `json:"autoPrune,omitempty" jsonschema:"whether automated sync prunes resources, requires an automated sync policy"`
+	SelfHeal             *bool    `json:"selfHeal,omitempty" jsonschema:"whether automated sync reverts changes made in the cluster, requires an automated sync policy"`
+	DestinationNamespace string   `json:"destinationNamespace,omitempty" jsonschema:"new destination namespace"`
+	DryRun               bool     `json:"dryRun,omitempty" jsonschema:"return the diff without updating the application"`
+}
+
+// UpdateApplicationOutput defines the output structure for editing an application's spec
+type UpdateApplicationOutput struct {
+	Name    string `json:"name"`
+	DryRun  bool   `json:"dryRun"`
+	Changed bool   `json:"changed" jsonschema:"false if the edits left the spec unchanged"`
+	Diff    string `json:"diff,omitempty" jsonschema:"unified diff of the spec before and after the edits"`
+}
+
+// NewUpdateApplicationHandler creates an UpdateApplication handler with the provided AppContext
+func NewUpdateApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, UpdateApplicationInput) (*mcp.CallToolResult, UpdateApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input UpdateApplicationInput) (*mcp.CallToolResult, UpdateApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_update_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("update_application completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, UpdateApplicationOutput{}, fmt.Errorf("application name is required")
+		}
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, UpdateApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		// Edit the live spec, since the cached one may be stale
+		query := &application.ApplicationQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+		app, err := appClient.Get(ctx, query)
+		if err != nil {
+			return nil, UpdateApplicationOutput{}, fmt.Errorf("failed to get application %q: %w", input.Name, err)
+		}
+
+		spec := app.Spec.DeepCopy()
+		if err := applySpecEdits(spec, input); err != nil {
+			return nil, UpdateApplicationOutput{}, fmt.Errorf("cannot update application %q: %w", input.Name, err)
+		}
+
+		diff, err := specDiff(&app.Spec, spec)
+		if err != nil {
+			return nil, UpdateApplicationOutput{}, err
+		}
+		output := UpdateApplicationOutput{
+			Name:    input.Name,
+			DryRun:  input.DryRun,
+			Changed: diff != "",
+			Diff:    diff,
+		}
+		if !output.Changed {
+			return nil, output, nil
+		}
+
+		// UpdateSpec validates too, but a dry run has to catch the same errors without calling it
+		if err := validateSpec(ctx, appCtx, app, spec); err != nil {
+			return nil, UpdateApplicationOutput{}, fmt.Errorf("cannot update application %q: %w", input.Name, err)
+		}
+		if input.DryRun {
+			return nil, output, nil
+		}
+
+		l.Infow("Updating application spec", "name", input.Name)
+		validate := true
+		updateRequest := &application.ApplicationUpdateSpecRequest{
+			Name:         &app.Name,
+			AppNamespace: &app.Namespace,
+			Spec:         spec,
+			Validate:     &validate,
+		}
+		updated, err := appClient.UpdateSpec(ctx, updateRequest)
+		if err != nil {
+			return nil, UpdateApplicationOutput{}, fmt.Errorf("failed to update application %q: %w", input.Name, err)
+		}
+
+		app.Spec = *updated
+		appCtx.UpsertCachedApplication(app)
+
+		return nil, output, nil
+	}
+}
+
+// applySpecEdits applies the requested edits to an application spec
+func applySpecEdits(spec *v1alpha1.ApplicationSpec, input UpdateApplicationInput) error {
+	sourceEdits := input.Revision != "" || len(input.HelmParameters) > 0 || len(input.HelmValueFiles) > 0 ||
+		len(input.KustomizeImages) > 0 || input.KustomizeNamePrefix != nil
+	policyEdits := input.SyncPolicy != "" || input.AutoPrune != nil || input.SelfHeal != nil
+	if !sourceEdits && !policyEdits && input.DestinationNamespace == "" {
+		return fmt.Errorf("no changes requested")
+	}
+
+	if sourceEdits {
+		source, err := editableSource(spec, input.SourcePosition)
+		if err != nil {
+			return err
+		}
+		if err := applySourceEdits(source, input); err != nil {
+			return err
+		}
+	}
+
+	if policyEdits {
+		if err := applySyncPolicyEdits(spec, input); err != nil {
+			return err
+		}
+	}
+
+	if input.DestinationNamespace != "" {
+		spec.Destination.Namespace = input.DestinationNamespace
+	}
+
+	return nil
+}
+
+// editableSource returns the source to edit, selected by its 1-based position for multi-source applications
+func editableSource(spec *v1alpha1.ApplicationSpec, position int) (*v1alpha1.ApplicationSource, error) {
+	if spec.SourceHydrator != nil {
+		return nil, fmt.Errorf("sources of applications using the source hydrator can't be edited")
+	}
+	if spec.HasMultipleSources() {
+		if position < 1 || position > len(spec.Sources) {
+			return nil, fmt.Errorf("application has %d sources, sourcePosition must be between 1 and %d", len(spec.Sources), len(spec.Sources))
+		}
+		return &spec.Sources[position-1], nil
+	}
+	if position > 1 {
+		return nil, fmt.Errorf("application has a single source, sourcePosition must be 1 or omitted")
+	}
+	if spec.Source == nil {
+		return nil, fmt.Errorf("application has no source")
+	}
+	return spec.Source, nil
+}
+
+// applySourceEdits applies revision, Helm and Kustomize edits to a source
+func applySourceEdits(source *v1alpha1.ApplicationSource, input UpdateApplicationInput) error {
+	if input.Revision != "" {
+		source.TargetRevision = input.Revision
+	}
+
+	if len(input.HelmParameters) > 0 || len(input.HelmValueFiles) > 0 {
+		if source.Helm == nil {
+			source.Helm = &v1alpha1.ApplicationSourceHelm{}
+		}
+		for _, text := range input.HelmParameters {
+			param, err := v1alpha1.NewHelmParameter(text, false)
+			if err != nil {
+				return fmt.Errorf("invalid Helm parameter %q, expected name=value", text)
+			}
+			source.Helm.AddParameter(*param)
+		}
+		if len(input.HelmValueFiles) > 0 {
+			source.Helm.ValueFiles = input.HelmValueFiles
+		}
+	}
+
+	if len(input.KustomizeImages) > 0 || input.KustomizeNamePrefix != nil {
+		if source.Kustomize == nil {
+			source.Kustomize = &v1alpha1.ApplicationSourceKustomize{}
+		}
+		for _, image := range input.KustomizeImages {
+			source.Kustomize.MergeImage(v1alpha1.KustomizeImage(image))
+		}
+		if input.KustomizeNamePrefix != nil {
+			source.Kustomize.NamePrefix = *input.KustomizeNamePrefix
+		}
+		if source.Kustomize.IsZero() {
+			source.Kustomize = nil
+		}
+	}
+
+	return nil
+}
+
+// applySyncPolicyEdits enables or disables automated sync and sets its prune and self-heal options
+func applySyncPolicyEdits(spec *v1alpha1.ApplicationSpec, input UpdateApplicationInput) error {
+	switch input.SyncPolicy {
+	case "":
+	case SyncPolicyAutomated:
+		if spec.SyncPolicy == nil {
+			spec.SyncPolicy = &v1alpha1.SyncPolicy{}
+		}
+		if spec.SyncPolicy.Automated == nil {
+			spec.SyncPolicy.Automated = &v1alpha1.SyncPolicyAutomated{}
+		}
+	case SyncPolicyNone:
+		if spec.SyncPolicy != nil {
+			spec.SyncPolicy.Automated = nil
+			if spec.SyncPolicy.IsZero() {
+				spec.SyncPolicy = nil
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported sync policy %q, expected %q or %q", input.SyncPolicy, SyncPolicyAutomated, SyncPolicyNone)
+	}
+
+	if input.AutoPrune == nil && input.SelfHeal == nil {
+		return nil
+	}
+	if spec.SyncPolicy == nil || spec.SyncPolicy.Automated == nil {
+		return fmt.Errorf("autoPrune and selfHeal require an automated sync policy")
+	}
+	if input.AutoPrune != nil {
+		spec.SyncPolicy.Automated.Prune = *input.AutoPrune
+	}
+	if input.SelfHeal != nil {
+		spec.SyncPolicy.Automated.SelfHeal = *input.SelfHeal
+	}
+	return nil
+}
+
+// validateSpec checks an edited spec the way Argo CD does when it is saved with validation
+// The project has to permit its sources and destination, and every source has to resolve in its repository
+func validateSpec(ctx context.Context, appCtx *appcontext.AppContext, app *v1alpha1.Application, spec *v1alpha1.ApplicationSpec) error {
+	edited := app.DeepCopy()
+	edited.Spec = *spec
+
+	proj, err := getProject(ctx, appCtx, spec.Project)
+	if err != nil {
+		return err
+	}
+	clusters := func() ([]v1alpha1.Cluster, error) {
+		return loadClusters(ctx, appCtx)
+	}
+	if err := validateProjectPermits(proj, edited, clusters); err != nil {
+		return err
+	}
+
+	conn, repoClient, err := appCtx.ArgoClient.NewRepoClient()
+	if err != nil {
+		return fmt.Errorf("failed to create repository client: %w", err)
+	}
+	defer conn.Close()
+
+	return validateSpecSources(ctx, repoClient, edited)
+}
+
+// validateSpecSources checks that the repository server can resolve every source of an application
+// Sources that only provide a ref for other sources' value files have nothing to resolve and are skipped
+func validateSpecSources(ctx context.Context, repoClient repository.RepositoryServiceClient, app *v1alpha1.Application) error {
+	for i, source := range app.Spec.GetSources() {
+		if source.Ref != "" && source.Path == "" && source.Chart == "" {
+			continue
+		}
+		query := &repository.RepoAppDetailsQuery{
+			Source:     &source,
+			AppName:    app.Name,
+			AppProject: app.Spec.Project,
+		}
+		if app.Spec.HasMultipleSources() {
+			query.SourceIndex = int32(i)
+		}
+		if _, err := repoClient.GetAppDetails(ctx, query); err != nil {
+			return fmt.Errorf("source %d (%s) is invalid: %w", i, source.RepoURL, err)
+		}
+	}
+	return nil
+}
+
+// specDiff renders a unified diff of two application specs as YAML
+func specDiff(before, after *v1alpha1.ApplicationSpec) (string, error) {
+	from, err := yaml.Marshal(before)
+	if err != nil {
+		return "", fmt.Errorf("failed to render spec: %w", err)
+	}
+	to, err := yaml.Marshal(after)
+	if err != nil {
+		return "", fmt.Errorf("failed to render spec: %w", err)
+	}
+	return unifiedDiff("before", "after", string(from), string(to), DefaultDiffContextLines), nil
+}
@@ -0,0 +1,197 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// appDetailsRepoClient answers GetAppDetails, failing for sources with an unknown path
+type appDetailsRepoClient struct {
+	repository.RepositoryServiceClient
+	queries []*repository.RepoAppDetailsQuery
+}
+
+func (c *appDetailsRepoClient) GetAppDetails(_ context.Context, query *repository.RepoAppDetailsQuery, _ ...grpc.CallOption) (*apiclient.RepoAppDetailsResponse, error) {
+	c.queries = append(c.queries, query)
+	if query.Source.Path == "missing" {
+		return nil, fmt.Errorf("app path does not exist")
+	}
+	return &apiclient.RepoAppDetailsResponse{}, nil
+}
+
+var _ = Describe("Update Application", func() {
+	var spec *v1alpha1.ApplicationSpec
+
+	BeforeEach(func() {
+		spec = &v1alpha1.ApplicationSpec{
+			Source: &v1alpha1.ApplicationSource{
+				RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+				Path:           "helm-guestbook",
+				TargetRevision: "HEAD",
+				Helm: &v1alpha1.ApplicationSourceHelm{
+					Parameters: []v1alpha1.HelmParameter{{Name: "replicas", Value: "1"}},
+				},
+			},
+			Destination: v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "default"},
+		}
+	})
+
+	Describe("applySpecEdits", func() {
+		It("should reject an empty edit", func() {
+			Expect(applySpecEdits(spec, UpdateApplicationInput{Name: "guestbook"})).To(MatchError("no changes requested"))
+		})
+
+		It("should set revision, Helm parameters and value files", func() {
+			err := applySpecEdits(spec, UpdateApplicationInput{
+				Revision:       "v1.2.0",
+				HelmParameters: []string{"replicas=3", "image.tag=1.27"},
+				HelmValueFiles: []string{"values-prod.yaml"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Source.TargetRevision).To(Equal("v1.2.0"))
+			Expect(spec.Source.Helm.Parameters).To(Equal([]v1alpha1.HelmParameter{
+				{Name: "replicas", Value: "3"},
+				{Name: "image.tag", Value: "1.27"},
+			}))
+			Expect(spec.Source.Helm.ValueFiles).To(Equal([]string{"values-prod.yaml"}))
+		})
+
+		It("should reject malformed Helm parameters", func() {
+			err := applySpecEdits(spec, UpdateApplicationInput{HelmParameters: []string{"replicas"}})
+			Expect(err).To(MatchError(ContainSubstring(`invalid Helm parameter "replicas"`)))
+		})
+
+		It("should merge Kustomize images and set the name prefix", func() {
+			spec.Source.Kustomize = &v1alpha1.ApplicationSourceKustomize{Images: v1alpha1.KustomizeImages{"nginx=nginx:1.25", "redis:7"}}
+			prefix := "prod-"
+			err := applySpecEdits(spec, UpdateApplicationInput{
+				KustomizeImages:     []string{"nginx=nginx:1.27"},
+				KustomizeNamePrefix: &prefix,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Source.Kustomize.Images).To(Equal(v1alpha1.KustomizeImages{"nginx=nginx:1.27", "redis:7"}))
+			Expect(spec.Source.Kustomize.NamePrefix).To(Equal("prod-"))
+		})
+
+		It("should drop the Kustomize section when the name prefix is cleared", func() {
+			spec.Source.Kustomize = &v1alpha1.ApplicationSourceKustomize{NamePrefix: "prod-"}
+			prefix := ""
+			Expect(applySpecEdits(spec, UpdateApplicationInput{KustomizeNamePrefix: &prefix})).To(Succeed())
+			Expect(spec.Source.Kustomize).To(BeNil())
+		})
+
+		It("should set the destination namespace", func() {
+			Expect(applySpecEdits(spec, UpdateApplicationInput{DestinationNamespace: "guestbook"})).To(Succeed())
+			Expect(spec.Destination.Namespace).To(Equal("guestbook"))
+		})
+
+		Context("with multiple sources", func() {
+			BeforeEach(func() {
+				spec.Sources = v1alpha1.ApplicationSources{*spec.Source, {RepoURL: "https://charts.example.com", Chart: "redis", TargetRevision: "17.0.0"}}
+				spec.Source = nil
+			})
+
+			It("should require a source position", func() {
+				err := applySpecEdits(spec, UpdateApplicationInput{Revision: "18.0.0"})
+				Expect(err).To(MatchError(ContainSubstring("sourcePosition must be between 1 and 2")))
+			})
+
+			It("should edit the selected source", func() {
+				Expect(applySpecEdits(spec, UpdateApplicationInput{Revision: "18.0.0", SourcePosition: 2})).To(Succeed())
+				Expect(spec.Sources[0].TargetRevision).To(Equal("HEAD"))
+				Expect(spec.Sources[1].TargetRevision).To(Equal("18.0.0"))
+			})
+		})
+	})
+
+	Describe("applySyncPolicyEdits", func() {
+		boolPtr := func(b bool) *bool { return &b }
+
+		It("should enable automated sync with prune and self-heal", func() {
+			err := applySyncPolicyEdits(spec, UpdateApplicationInput{SyncPolicy: SyncPolicyAutomated, AutoPrune: boolPtr(true), SelfHeal: boolPtr(true)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.SyncPolicy.Automated).To(Equal(&v1alpha1.SyncPolicyAutomated{Prune: true, SelfHeal: true}))
+		})
+
+		It("should disable automated sync and drop an empty policy", func() {
+			spec.SyncPolicy = &v1alpha1.SyncPolicy{Automated: &v1alpha1.SyncPolicyAutomated{Prune: true}}
+			Expect(applySyncPolicyEdits(spec, UpdateApplicationInput{SyncPolicy: SyncPolicyNone})).To(Succeed())
+			Expect(spec.SyncPolicy).To(BeNil())
+		})
+
+		It("should keep sync options when disabling automated sync", func() {
+			spec.SyncPolicy = &v1alpha1.SyncPolicy{Automated: &v1alpha1.SyncPolicyAutomated{}, SyncOptions: v1alpha1.SyncOptions{"CreateNamespace=true"}}
+			Expect(applySyncPolicyEdits(spec, UpdateApplicationInput{SyncPolicy: SyncPolicyNone})).To(Succeed())
+			Expect(spec.SyncPolicy.Automated).To(BeNil())
+			Expect(spec.SyncPolicy.SyncOptions).To(HaveLen(1))
+		})
+
+		It("should require automated sync for prune and self-heal", func() {
+			err := applySyncPolicyEdits(spec, UpdateApplicationInput{SelfHeal: boolPtr(true)})
+			Expect(err).To(MatchError(ContainSubstring("require an automated sync policy")))
+		})
+
+		It("should reject unknown policies", func() {
+			Expect(applySyncPolicyEdits(spec, UpdateApplicationInput{SyncPolicy: "sometimes"})).To(MatchError(ContainSubstring(`unsupported sync policy "sometimes"`)))
+		})
+	})
+
+	Describe("specDiff", func() {
+		It("should show the changed fields", func() {
+			after := spec.DeepCopy()
+			after.Source.TargetRevision = "v1.2.0"
+
+			diff, err := specDiff(spec, after)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(ContainSubstring("-  targetRevision: HEAD"))
+			Expect(diff).To(ContainSubstring("+  targetRevision: v1.2.0"))
+		})
+
+		It("should be empty when nothing changed", func() {
+			diff, err := specDiff(spec, spec.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(BeEmpty())
+		})
+	})
+
+	Describe("validateSpecSources", func() {
+		var repoClient *appDetailsRepoClient
+
+		BeforeEach(func() {
+			repoClient = &appDetailsRepoClient{}
+		})
+
+		It("should resolve each source in its repository", func() {
+			app := &v1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "guestbook"},
+				Spec: v1alpha1.ApplicationSpec{
+					Project: "team-a",
+					Sources: v1alpha1.ApplicationSources{
+						{RepoURL: "https://git/app", Path: "deploy"},
+						{RepoURL: "https://git/values", Ref: "values"},
+						{RepoURL: "https://charts", Chart: "web", TargetRevision: "1.0.0"},
+					},
+				},
+			}
+			Expect(validateSpecSources(context.Background(), repoClient, app)).To(Succeed())
+			Expect(repoClient.queries).To(HaveLen(2))
+			Expect(repoClient.queries[1].SourceIndex).To(Equal(int32(2)))
+			Expect(repoClient.queries[1].AppProject).To(Equal("team-a"))
+		})
+
+		It("should reject a source that doesn't resolve", func() {
+			spec.Source.Path = "missing"
+			app := &v1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "guestbook"}, Spec: *spec}
+			Expect(validateSpecSources(context.Background(), repoClient, app)).To(MatchError(ContainSubstring("source 0 (https://github.com/argoproj/argocd-example-apps) is invalid: app path does not exist")))
+		})
+	})
+})
@@ -0,0 +1,271 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"template_cli/internal/appcontext"
+	"template_cli/internal/log"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// WaitConditionSynced is met when the application is in sync with git
+	WaitConditionSynced = "synced"
+
+	// WaitConditionHealthy is met when the application is healthy
+	WaitConditionHealthy = "healthy"
+
+	// WaitConditionOperationFinished is met when no operation is queued or running
+	WaitConditionOperationFinished = "operationFinished"
+
+	// WaitConditionSuspended is met when the application is suspended
+	WaitConditionSuspended = "suspended"
+
+	// DefaultWaitTimeoutSeconds is how long to wait when no timeout is given
+	DefaultWaitTimeoutSeconds = 300
+
+	// MaxWaitTimeoutSeconds is the longest a caller may wait
+	MaxWaitTimeoutSeconds = 1800
+
+	// watchReopenDelay is the pause before re-opening a watch stream the server ended
+	watchReopenDelay = time.Second
+)
+
+// defaultWaitConditions match the defaults of argocd app wait
+var defaultWaitConditions = []string{WaitConditionSynced, WaitConditionHealthy, WaitConditionOperationFinished}
+
+// WaitForApplicationInput defines the input parameters for waiting on an application
+type WaitForApplicationInput struct {
+	Name           string   `json:"name" jsonschema:"application name"`
+	AppNamespace   string   `json:"appNamespace,omitempty" jsonschema:"optional namespace the Application resource lives in"`
+	Conditions     []string `json:"conditions,omitempty" jsonschema:"conditions that must all hold: synced, healthy, operationFinished, suspended (default synced, healthy and operationFinished)"`
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty" jsonschema:"optional time to wait before giving up (default 300, max 1800)"`
+}
+
+// WaitForApplicationOutput defines the output structure for waiting on an application
+type WaitForApplicationOutput struct {
+	Name           string             `json:"name"`
+	Conditions     []string           `json:"conditions" jsonschema:"conditions that were waited for"`
+	Satisfied      bool               `json:"satisfied" jsonschema:"true if all conditions were met before the timeout"`
+	ElapsedSeconds float64            `json:"elapsedSeconds"`
+	Final          ApplicationState   `json:"final" jsonschema:"last observed state of the application"`
+	Timeline       []ApplicationState `json:"timeline" jsonschema:"observed state transitions, oldest first"`
+}
+
+// ApplicationState is a point-in-time view of an application's sync, health and operation status
+type ApplicationState struct {
+	Time           string `json:"time,omitempty"`
+	SyncStatus     string `json:"syncStatus"`
+	HealthStatus   string `json:"healthStatus"`
+	OperationPhase string `json:"operationPhase,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+// NewWaitForApplicationHandler creates a WaitForApplication handler with the provided AppContext
+func NewWaitForApplicationHandler(appCtx *appcontext.AppContext) func(context.Context, *mcp.CallToolRequest, WaitForApplicationInput) (*mcp.CallToolResult, WaitForApplicationOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input WaitForApplicationInput) (*mcp.CallToolResult, WaitForApplicationOutput, error) {
+		l := log.Logger().With("component", "argocd_wait_for_application")
+		startTime := time.Now()
+		defer func() {
+			duration := time.Since(startTime)
+			l.Infow("wait_for_application completed", "duration", duration)
+		}()
+
+		if input.Name == "" {
+			return nil, WaitForApplicationOutput{}, fmt.Errorf("application name is required")
+		}
+		conditions, err := waitConditions(input.Conditions)
+		if err != nil {
+			return nil, WaitForApplicationOutput{}, err
+		}
+
+		timeoutSeconds := input.TimeoutSeconds
+		if timeoutSeconds <= 0 {
+			timeoutSeconds = DefaultWaitTimeoutSeconds
+		}
+		timeoutSeconds = min(timeoutSeconds, MaxWaitTimeoutSeconds)
+
+		conn, appClient, err := appCtx.ArgoClient.NewApplicationClient()
+		if err != nil {
+			return nil, WaitForApplicationOutput{}, fmt.Errorf("failed to create application client: %w", err)
+		}
+		defer conn.Close()
+
+		query := &application.ApplicationQuery{Name: &input.Name}
+		if input.AppNamespace != "" {
+			query.AppNamespace = &input.AppNamespace
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+		defer cancel()
+
+		tracker := newWaitTracker(conditions, newProgressNotifier(ctx, req))
+		var final *v1alpha1.Application
+		satisfied := false
+
+		// The server ends watch streams periodically, so re-open until satisfied or timed out
+		// Re-opens are spaced out, so a stream that ends straight away doesn't hammer the API server
+		for attempt := 0; !satisfied && waitCtx.Err() == nil; attempt++ {
+			if attempt > 0 && !sleepContext(waitCtx, watchReopenDelay) {
+				break
+			}
+			stream, err := appClient.Watch(waitCtx, query)
+			if err != nil {
+				if waitCtx.Err() != nil {
+					break
+				}
+				return nil, WaitForApplicationOutput{}, fmt.Errorf("failed to watch application %q: %w", input.Name, err)
+			}
+
+			var last *v1alpha1.Application
+			last, satisfied, err = watchApplication(stream.Recv, tracker.observe)
+			if last != nil {
+				final = last
+			}
+			if err != nil && waitCtx.Err() == nil {
+				return nil, WaitForApplicationOutput{}, fmt.Errorf("failed to watch application %q: %w", input.Name, err)
+			}
+		}
+
+		if final != nil {
+			appCtx.UpsertCachedApplication(final)
+		}
+		l.Infow("Finished waiting", "name", input.Name, "satisfied", satisfied, "transitions", len(tracker.timeline))
+
+		output := WaitForApplicationOutput{
+			Name:           input.Name,
+			Conditions:     conditions,
+			Satisfied:      satisfied,
+			ElapsedSeconds: time.Since(startTime).Seconds(),
+			Timeline:       tracker.timeline,
+		}
+		if final != nil {
+			output.Final = applicationState(final)
+		}
+		return nil, output, nil
+	}
+}
+
+// waitConditions validates the requested conditions, applying the defaults when none are given
+func waitConditions(conditions []string) ([]string, error) {
+	if len(conditions) == 0 {
+		return defaultWaitConditions, nil
+	}
+	for _, condition := range conditions {
+		switch condition {
+		case WaitConditionSynced, WaitConditionHealthy, WaitConditionOperationFinished, WaitConditionSuspended:
+		default:
+			return nil, fmt.Errorf("unsupported condition %q, expected one of %s", condition, strings.Join([]string{WaitConditionSynced, WaitConditionHealthy, WaitConditionOperationFinished, WaitConditionSuspended}, ", "))
+		}
+	}
+	return conditions, nil
+}
+
+// conditionsMet reports whether an application meets all the given conditions
+func conditionsMet(app *v1alpha1.Application, conditions []string) bool {
+	for _, condition := range conditions {
+		var met bool
+		switch condition {
+		case WaitConditionSynced:
+			met = app.Status.Sync.Status == v1alpha1.SyncStatusCodeSynced
+		case WaitConditionHealthy:
+			met = app.Status.Health.Status == "Healthy"
+		case WaitConditionSuspended:
+			met = app.Status.Health.Status == "Suspended"
+		case WaitConditionOperationFinished:
+			met = !operationInProgress(app)
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+// applicationState captures the sync, health and operation status of an application
+func applicationState(app *v1alpha1.Application) ApplicationState {
+	state := ApplicationState{
+		SyncStatus:   string(app.Status.Sync.Status),
+		HealthStatus: string(app.Status.Health.Status),
+		Message:      app.Status.Health.Message,
+	}
+	if app.Status.OperationState != nil {
+		state.OperationPhase = string(app.Status.OperationState.Phase)
+		if app.Status.OperationState.Message != "" {
+			state.Message = app.Status.OperationState.Message
+		}
+	}
+	return state
+}
+
+// waitTracker records state transitions of an application and checks them against the wait conditions
+type waitTracker struct {
+	conditions []string
+	notify     func(message string)
+	timeline   []ApplicationState
+}
+
+// newWaitTracker creates a tracker for the given conditions that reports each transition to notify
+func newWaitTracker(conditions []string, notify func(message string)) *waitTracker {
+	return &waitTracker{
+		conditions: conditions,
+		notify:     notify,
+		timeline:   make([]ApplicationState, 0),
+	}
+}
+
+// observe records the application's state if it changed and reports whether the conditions are met
+func (t *waitTracker) observe(app *v1alpha1.Application) bool {
+	state := applicationState(app)
+	if len(t.timeline) == 0 || !sameStatus(t.timeline[len(t.timeline)-1], state) {
+		state.Time = formatGoTime(time.Now())
+		t.timeline = append(t.timeline, state)
+		t.notify(fmt.Sprintf("sync=%s health=%s operation=%s", state.SyncStatus, state.HealthStatus, state.OperationPhase))
+	}
+	return conditionsMet(app, t.conditions)
+}
+
+// sameStatus reports whether two states have the same sync, health and operation status
+func sameStatus(a, b ApplicationState) bool {
+	return a.SyncStatus == b.SyncStatus && a.HealthStatus == b.HealthStatus && a.OperationPhase == b.OperationPhase
+}
+
+// sleepContext waits for the given duration and reports whether it did so before the context ended
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// newProgressNotifier returns a function that sends MCP progress notifications for the request
+// Notifications are only sent if the client asked for them by passing a progress token
+func newProgressNotifier(ctx context.Context, req *mcp.CallToolRequest) func(message string) {
+	if req == nil || req.Session == nil || req.Params == nil || req.Params.GetProgressToken() == nil {
+		return func(string) {}
+	}
+
+	token := req.Params.GetProgressToken()
+	progress := 0.0
+	return func(message string) {
+		progress++
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      progress,
+			Message:       message,
+		})
+		if err != nil {
+			log.Logger().Warnw("Failed to send progress notification", "error", err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package argo
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+)
+
+// statusEvent builds a Modified watch event with the given sync, health and operation phase
+func statusEvent(sync, healthStatus, phase string) *v1alpha1.ApplicationWatchEvent {
+	event := watchEvent(phase, nil)
+	event.Application.Status.Sync.Status = v1alpha1.SyncStatusCode(sync)
+	event.Application.Status.Health.Status = health.HealthStatusCode(healthStatus)
+	return event
+}
+
+var _ = Describe("Wait For Application", func() {
+	Describe("waitConditions", func() {
+		It("should default to synced, healthy and operationFinished", func() {
+			conditions, err := waitConditions(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conditions).To(Equal([]string{WaitConditionSynced, WaitConditionHealthy, WaitConditionOperationFinished}))
+		})
+
+		It("should reject unknown conditions", func() {
+			_, err := waitConditions([]string{WaitConditionSynced, "degraded"})
+			Expect(err).To(MatchError(ContainSubstring(`unsupported condition "degraded"`)))
+		})
+	})
+
+	DescribeTable("conditionsMet",
+		func(sync, healthStatus, phase string, conditions []string, expected bool) {
+			app := &statusEvent(sync, healthStatus, phase).Application
+			Expect(conditionsMet(app, conditions)).To(Equal(expected))
+		},
+		Entry("synced and healthy", "Synced", "Healthy", "Succeeded", defaultWaitConditions, true),
+		Entry("still progressing", "Synced", "Progressing", "Succeeded", defaultWaitConditions, false),
+		Entry("operation running", "Synced", "Healthy", "Running", defaultWaitConditions, false),
+		Entry("out of sync", "OutOfSync", "Healthy", "", []string{WaitConditionSynced}, false),
+		Entry("health only", "OutOfSync", "Healthy", "", []string{WaitConditionHealthy}, true),
+		Entry("suspended", "Synced", "Suspended", "", []string{WaitConditionSuspended}, true),
+		Entry("operation finished", "OutOfSync", "Degraded", "Failed", []string{WaitConditionOperationFinished}, true),
+	)
+
+	Describe("waitTracker", func() {
+		It("should record only transitions and notify for each", func() {
+			var messages []string
+			tracker := newWaitTracker(defaultWaitConditions, func(message string) {
+				messages = append(messages, message)
+			})
+			recv := fakeWatchStream(
+				statusEvent("OutOfSync", "Healthy", "Running"),
+				statusEvent("OutOfSync", "Healthy", "Running"),
+				statusEvent("Synced", "Progressing", "Succeeded"),
+				statusEvent("Synced", "Healthy", "Succeeded"),
+				statusEvent("Synced", "Degraded", "Succeeded"),
+			)
+
+			final, done, err := watchApplication(recv, tracker.observe)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(string(final.Status.Health.Status)).To(Equal("Healthy"))
+			Expect(tracker.timeline).To(HaveLen(3))
+			Expect(tracker.timeline[0].SyncStatus).To(Equal("OutOfSync"))
+			Expect(tracker.timeline[0].Time).NotTo(BeEmpty())
+			Expect(tracker.timeline[2].HealthStatus).To(Equal("Healthy"))
+			Expect(messages).To(Equal([]string{
+				"sync=OutOfSync health=Healthy operation=Running",
+				"sync=Synced health=Progressing operation=Succeeded",
+				"sync=Synced health=Healthy operation=Succeeded",
+			}))
+		})
+	})
+
+	Describe("applicationState", func() {
+		It("should prefer the operation message over the health message", func() {
+			app := &statusEvent("Synced", "Degraded", "Failed").Application
+			app.Status.Health.Message = "pod crashlooping"
+			Expect(applicationState(app).Message).To(Equal("pod crashlooping"))
+
+			app.Status.OperationState.Message = "hook failed"
+			Expect(applicationState(app)).To(Equal(ApplicationState{
+				SyncStatus:     "Synced",
+				HealthStatus:   "Degraded",
+				OperationPhase: "Failed",
+				Message:        "hook failed",
+			}))
+		})
+	})
+
+	Describe("newProgressNotifier", func() {
+		It("should be a no-op without a request", func() {
+			Expect(func() { newProgressNotifier(nil, nil)("message") }).NotTo(Panic())
+		})
+	})
+
+	Describe("sleepContext", func() {
+		It("should wait for the duration", func() {
+			start := time.Now()
+			Expect(sleepContext(context.Background(), 20*time.Millisecond)).To(BeTrue())
+			Expect(time.Since(start)).To(BeNumerically(">=", 20*time.Millisecond))
+		})
+
+		It("should stop when the context ends", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(sleepContext(ctx, time.Hour)).To(BeFalse())
+		})
+	})
+})